@@ -0,0 +1,104 @@
+package gomcp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// batchingSupported reports whether JSON-RPC batches may be used with the given protocol
+// version. Batching was added in 2025-03-26 and removed again in 2025-06-18; 2024-11-05 did not
+// rule it out.
+func batchingSupported(version string) bool {
+	return version != "" && version < protocol.ProtocolVersion20250618
+}
+
+// handleBatch handles a batch of messages. Notifications and responses are handled in order,
+// requests concurrently once all of them are registered. The responses to the requests are returned in the order of the batch.
+func (s *Server) handleBatch(ctx context.Context, batch []json.RawMessage) *HandlerResponse {
+	res, run := s.beginBatch(ctx, batch)
+	if run != nil {
+		return run()
+	}
+	return res
+}
+
+// beginBatch checks a batch and registers its requests as in flight before any of its messages
+// is handled, like begin does for single messages.
+func (s *Server) beginBatch(ctx context.Context, batch []json.RawMessage) (*HandlerResponse, func() *HandlerResponse) {
+	version := ""
+	if session := sessionFromContext(ctx); session != nil {
+		version = session.protocolVersion()
+	}
+	if !batchingSupported(version) {
+		return BadRequestResponse(NewErrorJsonRpcResponse(nil, &JsonRpcError{
+			Code:    ErrorCodeInvalidRequest,
+			Message: "Batching is not supported by the negotiated protocol version",
+			Data:    map[string]string{"protocolVersion": version},
+		})), nil
+	}
+	if len(batch) == 0 {
+		return BadRequestResponse(NewErrorJsonRpcResponse(nil, &JsonRpcError{
+			Code:    ErrorCodeInvalidRequest,
+			Message: "Invalid request",
+		})), nil
+	}
+
+	responses := make([]*JsonRpcResponse, len(batch))
+	runs := make([]func() *HandlerResponse, len(batch))
+	for i, raw := range batch {
+		var message JsonRpcRequest
+		if err := json.Unmarshal(raw, &message); err != nil {
+			responses[i] = NewErrorJsonRpcResponse(nil, &JsonRpcError{
+				Code:    ErrorCodeInvalidRequest,
+				Message: "Invalid request",
+			})
+			continue
+		}
+		if message.Method == "initialize" {
+			responses[i] = NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+				Code:    ErrorCodeInvalidRequest,
+				Message: "initialize must not be part of a batch",
+			})
+			continue
+		}
+		res, run := s.begin(ctx, &message)
+		if run == nil {
+			if res.SendBody {
+				responses[i] = res.Body
+			}
+			continue
+		}
+		runs[i] = run
+	}
+
+	return nil, func() *HandlerResponse {
+		var wg sync.WaitGroup
+		for i, run := range runs {
+			if run == nil {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if res := run(); res.SendBody {
+					responses[i] = res.Body
+				}
+			}()
+		}
+		wg.Wait()
+
+		bodies := make([]*JsonRpcResponse, 0, len(responses))
+		for _, res := range responses {
+			if res != nil {
+				bodies = append(bodies, res)
+			}
+		}
+		if len(bodies) == 0 {
+			return NotificationResponse()
+		}
+		return BatchResponse(bodies)
+	}
+}
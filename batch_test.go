@@ -0,0 +1,107 @@
+package gomcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+func handleTestBatch(t *testing.T, version string, batch string) (*HandlerResponse, *Session) {
+	t.Helper()
+	var messages []json.RawMessage
+	if err := json.Unmarshal([]byte(batch), &messages); err != nil {
+		t.Fatal(err)
+	}
+	session := NewSession()
+	session.ProtocolVersion = version
+	ctx := withSession(context.Background(), session)
+	return newTestServer().handleBatch(withSemaphore(ctx, newSemaphore(1)), messages), session
+}
+
+func TestBatchWithMixedEntries(t *testing.T) {
+	res, session := handleTestBatch(t, protocol.ProtocolVersion20250326, `[
+		{"jsonrpc":"2.0","id":1,"method":"ping"},
+		{"jsonrpc":"2.0","method":"notifications/initialized"},
+		{"jsonrpc":"2.0","id":"b","method":"tools/list"},
+		1,
+		{"jsonrpc":"1.0","id":2,"method":"ping"},
+		{"jsonrpc":"2.0","id":3,"method":"unknown"},
+		{"jsonrpc":"2.0","id":4,"method":"initialize","params":{}},
+		{"jsonrpc":"2.0","method":"notifications/unknown"}
+	]`)
+	if !res.SendBody {
+		t.Fatal("expected a response")
+	}
+	responses := decodeJson[[]map[string]any](t, res.payload())
+
+	want := []struct {
+		id   any
+		code float64
+	}{
+		{float64(1), 0},
+		{"b", 0},
+		{nil, ErrorCodeInvalidRequest},
+		{float64(2), ErrorCodeInvalidRequest},
+		{float64(3), ErrorCodeMethodNotFound},
+		{float64(4), ErrorCodeInvalidRequest},
+	}
+	if len(responses) != len(want) {
+		t.Fatalf("expected %d responses, got %v", len(want), responses)
+	}
+	for i, w := range want {
+		response := responses[i]
+		if response["id"] != w.id {
+			t.Errorf("response %d: expected id %v, got %v", i, w.id, response["id"])
+		}
+		if w.code == 0 {
+			if _, ok := response["result"]; !ok {
+				t.Errorf("response %d: expected a result, got %v", i, response)
+			}
+			continue
+		}
+		e, _ := response["error"].(map[string]any)
+		if e == nil || e["code"] != w.code {
+			t.Errorf("response %d: expected error %v, got %v", i, w.code, response)
+		}
+	}
+	if !session.isInitialized() {
+		t.Error("expected the notification to be handled")
+	}
+}
+
+func TestBatchOfNotificationsHasNoResponse(t *testing.T) {
+	res, _ := handleTestBatch(t, protocol.ProtocolVersion20250326, `[
+		{"jsonrpc":"2.0","method":"notifications/initialized"},
+		{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}
+	]`)
+	if res.SendBody {
+		t.Fatalf("expected no response, got %v", res.payload())
+	}
+}
+
+func TestBatchRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		batch   string
+	}{
+		{"2025-06-18", protocol.ProtocolVersion20250618, `[{"jsonrpc":"2.0","id":1,"method":"ping"}]`},
+		{"no session version", "", `[{"jsonrpc":"2.0","id":1,"method":"ping"}]`},
+		{"empty", protocol.ProtocolVersion20250326, `[]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, _ := handleTestBatch(t, tt.version, tt.batch)
+			response := decodeJson[map[string]any](t, res.payload())
+			e, _ := response["error"].(map[string]any)
+			if e == nil || e["code"] != float64(ErrorCodeInvalidRequest) {
+				t.Fatalf("expected an invalid request error, got %v", response)
+			}
+			if response["id"] != nil {
+				t.Fatalf("expected a null id, got %v", response["id"])
+			}
+		})
+	}
+}
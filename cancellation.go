@@ -0,0 +1,65 @@
+package gomcp
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// inFlightKey identifies a request by the session it belongs to and its id.
+func inFlightKey(ctx context.Context, id any) string {
+	sessionId := ""
+	if session := sessionFromContext(ctx); session != nil {
+		sessionId = session.Id
+	}
+	b, _ := json.Marshal(id)
+	return sessionId + "/" + string(b)
+}
+
+type inFlightRequest struct {
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
+}
+
+// track registers a request as in flight. The returned context is cancelled when the client
+// cancels the request. The returned function must be called once the request is done, it
+// reports whether the client cancelled the request.
+func (s *Server) track(ctx context.Context, id any) (context.Context, func() bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	key := inFlightKey(ctx, id)
+	request := &inFlightRequest{cancel: cancel}
+
+	s.inFlightMutex.Lock()
+	s.inFlight[key] = request
+	s.inFlightMutex.Unlock()
+
+	return ctx, func() bool {
+		s.inFlightMutex.Lock()
+		delete(s.inFlight, key)
+		s.inFlightMutex.Unlock()
+		cancel()
+		return request.cancelled.Load()
+	}
+}
+
+func (s *Server) handleCancelledNotification(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
+	var params protocol.CancelledNotificationParams
+	if r := s.mustParseParams(message, &params); r != nil {
+		return r
+	}
+	if !validId(params.RequestId) {
+		return NotificationResponse()
+	}
+
+	s.inFlightMutex.Lock()
+	request, ok := s.inFlight[inFlightKey(ctx, params.RequestId)]
+	s.inFlightMutex.Unlock()
+
+	if ok {
+		request.cancelled.Store(true)
+		request.cancel()
+	}
+	return NotificationResponse()
+}
@@ -0,0 +1,169 @@
+package gomcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// newBlockingServer returns a server with a tool that blocks until it is cancelled, and
+// channels receiving a value whenever the tool starts and stops.
+func newBlockingServer() (*Server, chan struct{}, chan struct{}) {
+	server := newTestServer()
+	started := make(chan struct{}, 1)
+	stopped := make(chan struct{}, 1)
+	server.AddTool(&Tool{
+		Name:        "block",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			stopped <- struct{}{}
+			return nil, ctx.Err()
+		},
+	})
+	return server, started, stopped
+}
+
+func await(t *testing.T, ch chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(testTimeout):
+		t.Fatal(what)
+	}
+}
+
+func TestCancelRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"numeric id", `7`},
+		{"string id", `"7"`},
+		{"string id with escapes", `"a\"b"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, started, stopped := newBlockingServer()
+			c := newStdioClient(t, server)
+			c.initialize(protocol.LatestProtocolVersion, `{}`)
+			c.send(`{"jsonrpc":"2.0","id":` + tt.id + `,"method":"tools/call","params":{"name":"block","arguments":{}}}`)
+			await(t, started, "tool did not start")
+
+			c.send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":` + tt.id + `,"reason":"test"}}`)
+			await(t, stopped, "tool was not cancelled")
+
+			// The cancelled request is not answered, later requests are.
+			c.expectNothing()
+			c.send(`{"jsonrpc":"2.0","id":"ping","method":"ping"}`)
+			if id := c.receive()["id"]; id != "ping" {
+				t.Fatalf("expected the ping response, got id %v", id)
+			}
+		})
+	}
+}
+
+func TestCancelRequestMatchesIdType(t *testing.T) {
+	server, started, stopped := newBlockingServer()
+	c := newStdioClient(t, server)
+	c.initialize(protocol.LatestProtocolVersion, `{}`)
+	c.send(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"block","arguments":{}}}`)
+	await(t, started, "tool did not start")
+
+	// "7" and 7 are different ids, so the request keeps running.
+	c.send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"7"}}`)
+	select {
+	case <-stopped:
+		t.Fatal("tool was cancelled by a request id of a different type")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	c.send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7}}`)
+	await(t, stopped, "tool was not cancelled")
+	c.expectNothing()
+}
+
+func TestCancelQueuedRequest(t *testing.T) {
+	server, started, stopped := newBlockingServer()
+	c := newStdioClient(t, server)
+	c.transport.SetMaxConcurrency(1)
+	c.initialize(protocol.LatestProtocolVersion, `{}`)
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"block","arguments":{}}}`)
+	await(t, started, "tool did not start")
+
+	// The second call waits for the first one, and is cancelled while it waits.
+	c.send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"block","arguments":{}}}`)
+	c.send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":2}}`)
+	c.send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}`)
+	await(t, stopped, "tool was not cancelled")
+
+	select {
+	case <-started:
+		t.Fatal("cancelled request was handled")
+	case <-time.After(50 * time.Millisecond):
+	}
+	c.expectNothing()
+}
+
+func TestCancelRequestInBatch(t *testing.T) {
+	server, started, _ := newBlockingServer()
+	c := newStdioClient(t, server)
+	c.initialize(protocol.ProtocolVersion20250326, `{}`)
+	c.send(`[{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"block","arguments":{}}},` +
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}]`)
+
+	// The request is registered before the cancellation is handled, so it never starts.
+	c.expectNothing()
+	select {
+	case <-started:
+		t.Fatal("cancelled request was handled")
+	default:
+	}
+}
+
+func TestHttpTransportAnswersCancelledRequests(t *testing.T) {
+	server, started, _ := newBlockingServer()
+	transport := NewHttpTransport(server)
+	session := NewSession()
+	session.ProtocolVersion = protocol.LatestProtocolVersion
+	if err := transport.sessionStore.Create(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	post := func(body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set("Accept", "application/json")
+		r.Header.Set(sessionIdHeader, session.Id)
+		w := httptest.NewRecorder()
+		transport.Handle(w, r)
+		return w
+	}
+
+	responses := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		responses <- post(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"block","arguments":{}}}`)
+	}()
+	await(t, started, "tool did not start")
+	if w := post(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}`); w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 for the notification, got %d", w.Code)
+	}
+
+	select {
+	case w := <-responses:
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+			t.Fatalf("expected a JSON response, got %d %q", w.Code, w.Header().Get("Content-Type"))
+		}
+		var response map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response["id"] != float64(1) {
+			t.Fatalf("expected a response to the request, got %s", w.Body)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("request was not answered")
+	}
+}
@@ -0,0 +1,60 @@
+package gomcp
+
+import (
+	"context"
+	"strings"
+)
+
+// ClientCapability names a capability a client can declare during initialization.
+type ClientCapability string
+
+const (
+	ClientCapabilityElicitation      ClientCapability = "elicitation"
+	ClientCapabilityRoots            ClientCapability = "roots"
+	ClientCapabilityRootsListChanged ClientCapability = "roots.listChanged"
+	ClientCapabilitySampling         ClientCapability = "sampling"
+)
+
+// ClientCapabilityExperimental names an experimental, non-standard capability.
+func ClientCapabilityExperimental(name string) ClientCapability {
+	return ClientCapability("experimental." + name)
+}
+
+// requiredCapabilities are the capabilities a client must declare to receive requests of the
+// given methods.
+var requiredCapabilities = map[string]ClientCapability{
+	"elicitation/create":     ClientCapabilityElicitation,
+	"roots/list":             ClientCapabilityRoots,
+	"sampling/createMessage": ClientCapabilitySampling,
+}
+
+// ClientSupports reports whether the client whose request is handled in ctx declared the given
+// capability during initialization.
+func ClientSupports(ctx context.Context, capability ClientCapability) bool {
+	session := sessionFromContext(ctx)
+	return session != nil && session.supports(capability)
+}
+
+func (s *Session) supports(capability ClientCapability) bool {
+	s.mutex.RLock()
+	c := s.ClientCapabilities
+	s.mutex.RUnlock()
+	if c == nil {
+		return false
+	}
+	switch capability {
+	case ClientCapabilityElicitation:
+		return c.Elicitation != nil
+	case ClientCapabilityRoots:
+		return c.Roots != nil
+	case ClientCapabilityRootsListChanged:
+		return c.Roots != nil && c.Roots.ListChanged
+	case ClientCapabilitySampling:
+		return c.Sampling != nil
+	}
+	if name, ok := strings.CutPrefix(string(capability), "experimental."); ok {
+		_, ok := c.Experimental[name]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package gomcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// ErrCapabilityNotSupported is returned when a request to the client requires a capability the
+// client did not declare.
+var ErrCapabilityNotSupported = errors.New("capability not supported by the client")
+
+type serverKey struct{}
+
+func withServer(ctx context.Context, s *Server) context.Context {
+	return context.WithValue(ctx, serverKey{}, s)
+}
+
+func serverFromContext(ctx context.Context) *Server {
+	s, _ := ctx.Value(serverKey{}).(*Server)
+	return s
+}
+
+// request sends a request to the client whose request is handled in ctx, and waits for the
+// response. The result is decoded into result. If the client answers with an error, it is
+// returned as *Error. If ctx is cancelled, the client is notified of the cancellation.
+//
+// Requests that need a capability the client did not declare are refused with
+// ErrCapabilityNotSupported.
+func request(ctx context.Context, method string, params any, result any) error {
+	if capability, ok := requiredCapabilities[method]; ok && !ClientSupports(ctx, capability) {
+		return ErrCapabilityNotSupported
+	}
+	s := serverFromContext(ctx)
+	sender := senderFromContext(ctx)
+	if s == nil || sender == nil {
+		return ErrNotConnected
+	}
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	id := strconv.FormatInt(s.nextRequestId.Add(1), 10)
+	key := inFlightKey(ctx, id)
+	response := make(chan *JsonRpcRequest, 1)
+
+	s.pendingMutex.Lock()
+	s.pending[key] = response
+	s.pendingMutex.Unlock()
+
+	defer func() {
+		s.pendingMutex.Lock()
+		delete(s.pending, key)
+		s.pendingMutex.Unlock()
+	}()
+
+	rawId, _ := json.Marshal(id)
+	if err := sender.send(&JsonRpcRequest{
+		Jsonrpc: "2.0",
+		Method:  method,
+		Params:  b,
+		Id:      rawId,
+	}); err != nil {
+		return err
+	}
+
+	select {
+	case message := <-response:
+		if message.Error != nil {
+			return message.Error
+		}
+		return json.Unmarshal(message.Result, result)
+	case <-sender.disconnected():
+		return ErrNotConnected
+	case <-ctx.Done():
+		sender.send(NewJsonRpcNotification("notifications/cancelled", &protocol.CancelledNotificationParams{
+			RequestId: rawId,
+			Reason:    ctx.Err().Error(),
+		}))
+		return ctx.Err()
+	}
+}
+
+// handleResponse passes a response of the client to the request waiting for it. Responses to
+// unknown requests, or to requests of other sessions, are dropped.
+func (s *Server) handleResponse(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
+	s.pendingMutex.Lock()
+	response, ok := s.pending[inFlightKey(ctx, message.Id)]
+	s.pendingMutex.Unlock()
+
+	if ok {
+		select {
+		case response <- message:
+		default:
+		}
+	}
+	return NotificationResponse()
+}
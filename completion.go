@@ -0,0 +1,62 @@
+package gomcp
+
+import (
+	"context"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// CompleteFunc returns completion values for a prompt argument or resource template variable.
+// It receives the partial value entered so far and the values of the arguments that have already
+// been resolved. At most protocol.MaxCompletionValues values are sent to the client.
+type CompleteFunc func(ctx context.Context, value string, arguments map[string]string) ([]string, error)
+
+func (s *Server) handleComplete(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
+	var params protocol.CompleteParams
+	if r := s.mustParseParams(message, &params); r != nil {
+		return r
+	}
+
+	var complete CompleteFunc
+	s.mutex.RLock()
+	switch params.Ref.Type {
+	case protocol.ReferenceTypePrompt:
+		for _, prompt := range s.prompts {
+			if prompt.Name != params.Ref.Name {
+				continue
+			}
+			for _, argument := range prompt.Arguments {
+				if argument.Name == params.Argument.Name {
+					complete = argument.Complete
+				}
+			}
+		}
+	case protocol.ReferenceTypeResource:
+		for _, template := range s.resourceTemplates {
+			if template.UriTemplate == params.Ref.Uri {
+				complete = template.Complete[params.Argument.Name]
+			}
+		}
+	default:
+		s.mutex.RUnlock()
+		return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+			Code:    ErrorCodeInvalidParams,
+			Message: "Invalid reference type",
+		}))
+	}
+	s.mutex.RUnlock()
+
+	if complete == nil {
+		return RequestResponse(NewResultJsonRpcResponse(message.Id, protocol.NewCompleteResult(nil)))
+	}
+
+	arguments := make(map[string]string)
+	if params.Context != nil && params.Context.Arguments != nil {
+		arguments = params.Context.Arguments
+	}
+	values, err := complete(ctx, params.Argument.Value, arguments)
+	if err != nil {
+		return ErrorResponse(message.Id, err)
+	}
+	return RequestResponse(NewResultJsonRpcResponse(message.Id, protocol.NewCompleteResult(values)))
+}
@@ -0,0 +1,47 @@
+package gomcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+	"github.com/cfichtmueller/gomcp/schema"
+)
+
+// Elicit asks the user of the client whose request is handled in ctx for information matching
+// requestedSchema, and waits for the answer. Use Decode on the result to read the submitted
+// data. It returns ErrCapabilityNotSupported if the client did not declare the elicitation
+// capability.
+func Elicit(ctx context.Context, message string, requestedSchema *protocol.ElicitRequestSchema) (*protocol.ElicitResult, error) {
+	if err := validateElicitSchema(requestedSchema); err != nil {
+		return nil, err
+	}
+	result := &protocol.ElicitResult{}
+	if err := request(ctx, "elicitation/create", protocol.NewElicitParams(message, requestedSchema), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateElicitSchema checks that the schema only has primitive properties, as required by the
+// specification.
+func validateElicitSchema(s *protocol.ElicitRequestSchema) error {
+	if s == nil {
+		return fmt.Errorf("requested schema is not set")
+	}
+	for name, property := range s.Properties {
+		p, ok := property.(schema.M)
+		if !ok {
+			p, ok = property.(map[string]any)
+		}
+		if !ok {
+			return fmt.Errorf("property %q is not a schema", name)
+		}
+		switch p["type"] {
+		case "string", "number", "integer", "boolean":
+		default:
+			return fmt.Errorf("property %q must have a primitive type, got %v", name, p["type"])
+		}
+	}
+	return nil
+}
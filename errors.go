@@ -0,0 +1,63 @@
+package gomcp
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// Error codes defined by JSON-RPC and MCP.
+const (
+	ErrorCodeParseError       = -32700
+	ErrorCodeInvalidRequest   = -32600
+	ErrorCodeMethodNotFound   = -32601
+	ErrorCodeInvalidParams    = -32602
+	ErrorCodeInternalError    = -32603
+	ErrorCodeResourceNotFound = -32002
+)
+
+// Error is a JSON-RPC error. Handlers return it to answer a request with a specific code and
+// data; other errors are reported to the client as internal errors.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// JsonRpcError is the name Error had before handlers could return it.
+type JsonRpcError = Error
+
+func NewError(code int, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) SetData(data any) *Error {
+	e.Data = data
+	return e
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// ErrorResponse answers the request with the given id with err. If err is not an *Error, it is
+// logged and the client receives an internal error without details.
+func ErrorResponse(id any, err error) *HandlerResponse {
+	var e *Error
+	if !errors.As(err, &e) {
+		slog.Error("Failed to handle request", "error", err)
+		e = NewError(ErrorCodeInternalError, "Internal error")
+	}
+	if e.Code == ErrorCodeInternalError {
+		return &HandlerResponse{
+			Status:   http.StatusInternalServerError,
+			SendBody: true,
+			Body:     NewErrorJsonRpcResponse(id, e),
+		}
+	}
+	return BadRequestResponse(NewErrorJsonRpcResponse(id, e))
+}
@@ -26,8 +26,8 @@ func main() {
 	server.AddResource(&gomcp.Resource{
 		Name: "user",
 		Uri:  "gomcp://user",
-		Handler: func(ctx context.Context) *protocol.ReadResourceResult {
-			return protocol.NewReadResourceResult().AddContent(protocol.NewTextResourceContents(ctx.Value(UserKey).(string), "gomcp://user"))
+		Handler: func(ctx context.Context) (*protocol.ReadResourceResult, error) {
+			return protocol.NewReadResourceResult().AddContent(protocol.NewTextResourceContents(ctx.Value(UserKey).(string), "gomcp://user")), nil
 		},
 	})
 
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/cfichtmueller/gomcp"
 	"github.com/cfichtmueller/gomcp/protocol"
@@ -22,18 +24,18 @@ func main() {
 		Title:       "Hello World",
 		Description: "This is a tool that says hello world",
 		InputSchema: protocol.NewInputSchema(),
-		Handler: func(ctx context.Context, arguments *gomcp.ToolArguments) *protocol.CallToolsResult {
-			return protocol.NewCallToolsResult().AddContent(protocol.NewTextContent().SetText("Hello world"))
+		Handler: func(ctx context.Context, arguments *gomcp.ToolArguments) (*protocol.CallToolsResult, error) {
+			return protocol.NewCallToolsResult().AddContent(protocol.NewTextContent().SetText("Hello world")), nil
 		},
 	})
 
 	server.AddResource(&gomcp.Resource{
 		Name: "test",
 		Uri:  "gomcp://test",
-		Handler: func(ctx context.Context) *protocol.ReadResourceResult {
+		Handler: func(ctx context.Context) (*protocol.ReadResourceResult, error) {
 			return protocol.NewReadResourceResult().AddContent(
 				protocol.NewTextResourceContents("Hello world", "gomcp://test").SetMimeType("text/plain"),
-			)
+			), nil
 		},
 	})
 
@@ -47,20 +49,38 @@ func main() {
 			SetRequired("a", "b"),
 		OutputSchema: protocol.NewOutputSchema().
 			SetProperty("result", protocol.NewNumberProperty("The result")),
-		Handler: func(ctx context.Context, arguments *gomcp.ToolArguments) *protocol.CallToolsResult {
+		Handler: func(ctx context.Context, arguments *gomcp.ToolArguments) (*protocol.CallToolsResult, error) {
 			a, err := arguments.Number("a")
 			if err != nil {
-				return protocol.NewCallToolsResult().AddContent(protocol.NewTextContent().SetText(err.Error())).SetIsError(true)
+				return nil, err
 			}
 			b, err := arguments.Number("b")
 			if err != nil {
-				return protocol.NewCallToolsResult().AddContent(protocol.NewTextContent().SetText(err.Error()))
+				return nil, err
 			}
 
 			return protocol.NewCallToolsResult().
 				SetStructuredContent(map[string]any{
 					"result": a + b,
-				})
+				}), nil
+		},
+	})
+
+	server.AddTool(&gomcp.Tool{
+		Name:        "count",
+		Title:       "Count",
+		Description: "Counts slowly to ten, reporting progress",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *gomcp.ToolArguments) (*protocol.CallToolsResult, error) {
+			for i := 1; i <= 10; i++ {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(500 * time.Millisecond):
+				}
+				gomcp.ReportProgress(ctx, float64(i), 10, fmt.Sprintf("Counted to %d", i))
+			}
+			return protocol.NewCallToolsResult().AddContent(protocol.NewTextContent().SetText("Counted to ten")), nil
 		},
 	})
 
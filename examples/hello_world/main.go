@@ -20,13 +20,13 @@ func main() {
 		InputSchema: protocol.NewInputSchema().
 			SetProperty("name", protocol.NewStringProperty("The name to say hello to")).
 			SetRequired("name"),
-		Handler: func(ctx context.Context, arguments *gomcp.ToolArguments) *protocol.CallToolsResult {
+		Handler: func(ctx context.Context, arguments *gomcp.ToolArguments) (*protocol.CallToolsResult, error) {
 			name, err := arguments.String("name")
 			if err != nil {
-				return protocol.NewCallToolsResult().AddContent(protocol.NewTextContent().SetText(err.Error())).SetIsError(true)
+				return nil, err
 			}
 			content := protocol.NewTextContent().SetText(fmt.Sprintf("Hello, %s!", name))
-			return protocol.NewCallToolsResult().AddContent(content)
+			return protocol.NewCallToolsResult().AddContent(content), nil
 		},
 	})
 
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/cfichtmueller/gomcp"
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+func main() {
+	addr := os.Getenv("LISTEM_ADDR")
+	if addr == "" {
+		addr = "127.0.0.1:8080"
+	}
+	server := gomcp.NewServer("prompts", "", "1.0.0")
+
+	server.AddPrompt(&gomcp.Prompt{
+		Name:        "code_review",
+		Title:       "Code Review",
+		Description: "Asks the LLM to review a piece of code",
+		Arguments: []*gomcp.PromptArgument{
+			{Name: "code", Description: "The code to review", Required: true},
+			{
+				Name:        "language",
+				Description: "The programming language of the code",
+				Complete: func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+					values := make([]string, 0)
+					for _, language := range []string{"go", "java", "javascript", "python", "rust"} {
+						if strings.HasPrefix(language, value) {
+							values = append(values, language)
+						}
+					}
+					return values, nil
+				},
+			},
+		},
+		Handler: func(ctx context.Context, arguments map[string]string) (*protocol.GetPromptResult, error) {
+			text := fmt.Sprintf("Please review this code:\n\n%s", arguments["code"])
+			if language := arguments["language"]; language != "" {
+				text = fmt.Sprintf("Please review this %s code:\n\n%s", language, arguments["code"])
+			}
+			return protocol.NewGetPromptResult().
+				SetDescription("Code review prompt").
+				AddMessage(protocol.NewPromptMessage(protocol.RoleUser, protocol.NewTextContent().SetText(text))), nil
+		},
+	})
+
+	server.AddPrompt(&gomcp.Prompt{
+		Name:        "style_guide",
+		Title:       "Style Guide",
+		Description: "Provides the style guide as context",
+		Handler: func(ctx context.Context, arguments map[string]string) (*protocol.GetPromptResult, error) {
+			guide := protocol.NewTextResourceContents("Use tabs for indentation.", "gomcp://style-guide").SetMimeType("text/plain")
+			return protocol.NewGetPromptResult().
+				AddMessage(protocol.NewPromptMessage(protocol.RoleUser, protocol.NewEmbeddedResource(guide))).
+				AddMessage(protocol.NewPromptMessage(protocol.RoleUser, protocol.NewTextContent().SetText("Follow the style guide above."))), nil
+		},
+	})
+
+	transport := gomcp.NewHttpTransport(server)
+	http.HandleFunc("/mcp", transport.Handle)
+	slog.Info("Starting server", "addr", addr)
+	http.ListenAndServe(addr, nil)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cfichtmueller/gomcp"
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+const uriPrefix = "gomcp://files/"
+
+// dirProvider exposes every file in a directory as a resource.
+type dirProvider struct {
+	root     *os.Root
+	pageSize int
+}
+
+func (p *dirProvider) List(ctx context.Context, cursor string) ([]*protocol.Resource, string, error) {
+	offset := 0
+	if cursor != "" {
+		o, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		offset = o
+	}
+	entries, err := fs.ReadDir(p.root.FS(), ".")
+	if err != nil {
+		return nil, "", err
+	}
+	resources := make([]*protocol.Resource, 0)
+	for i := offset; i < len(entries) && len(resources) < p.pageSize; i++ {
+		if entries[i].Type().IsRegular() {
+			resources = append(resources, protocol.NewResource(entries[i].Name(), uriPrefix+entries[i].Name()))
+		}
+		offset = i + 1
+	}
+	if offset >= len(entries) {
+		return resources, "", nil
+	}
+	return resources, strconv.Itoa(offset), nil
+}
+
+func (p *dirProvider) Read(ctx context.Context, uri string) (*protocol.ReadResourceResult, error) {
+	name, ok := strings.CutPrefix(uri, uriPrefix)
+	if !ok {
+		return nil, gomcp.ErrNoSuchResource
+	}
+	b, err := fs.ReadFile(p.root.FS(), name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, gomcp.ErrNoSuchResource
+	}
+	if err != nil {
+		return nil, err
+	}
+	return protocol.NewReadResourceResult().AddContent(protocol.NewTextResourceContents(string(b), uri)), nil
+}
+
+func main() {
+	addr := os.Getenv("LISTEM_ADDR")
+	if addr == "" {
+		addr = "127.0.0.1:8080"
+	}
+	dir := os.Getenv("FILES_DIR")
+	if dir == "" {
+		dir = "."
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		slog.Error("Failed to open directory", "dir", dir, "error", err)
+		os.Exit(1)
+	}
+	defer root.Close()
+
+	server := gomcp.NewServer("resource_provider", "", "1.0.0")
+	server.AddResourceProvider(&dirProvider{root: root, pageSize: 20})
+
+	transport := gomcp.NewHttpTransport(server)
+	http.HandleFunc("/mcp", transport.Handle)
+	slog.Info("Starting server", "addr", addr)
+	http.ListenAndServe(addr, nil)
+}
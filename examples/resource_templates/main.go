@@ -6,17 +6,11 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
-	"regexp"
 
 	"github.com/cfichtmueller/gomcp"
 	"github.com/cfichtmueller/gomcp/protocol"
 )
 
-var (
-	postsRegex = regexp.MustCompile(`^gomcp://posts/([a-zA-Z0-9]+)$`)
-	pagesRegex = regexp.MustCompile(`^gomcp://pages/([a-zA-Z0-9]+)$`)
-)
-
 func main() {
 	addr := os.Getenv("LISTEM_ADDR")
 	if addr == "" {
@@ -28,27 +22,20 @@ func main() {
 	server.AddResourceTemplate(&gomcp.ResourceTemplate{
 		Name:        "posts",
 		UriTemplate: "gomcp://posts/{id}",
-		Read: func(ctx context.Context, uri string) (*protocol.ReadResourceResult, error) {
-			matches := postsRegex.FindStringSubmatch(uri)
-			if matches == nil {
-				return nil, gomcp.ErrNoSuchResource
-			}
-			id := matches[1]
-			content := fmt.Sprintf("Post %s", id)
+		Handler: func(ctx context.Context, uri string, vars map[string]string) (*protocol.ReadResourceResult, error) {
+			content := fmt.Sprintf("Post %s", vars["id"])
 			return protocol.NewReadResourceResult().AddContent(protocol.NewTextResourceContents(content, uri)), nil
 		},
 	})
 
 	server.AddResourceTemplate(&gomcp.ResourceTemplate{
 		Name:        "pages",
-		UriTemplate: "gomcp://pages/{id}",
-		Read: func(ctx context.Context, uri string) (*protocol.ReadResourceResult, error) {
-			matches := pagesRegex.FindStringSubmatch(uri)
-			if matches == nil {
-				return nil, gomcp.ErrNoSuchResource
+		UriTemplate: "gomcp://pages/{id}{?lang}",
+		Handler: func(ctx context.Context, uri string, vars map[string]string) (*protocol.ReadResourceResult, error) {
+			content := fmt.Sprintf("Page %s", vars["id"])
+			if lang, ok := vars["lang"]; ok {
+				content = fmt.Sprintf("Page %s in %s", vars["id"], lang)
 			}
-			id := matches[1]
-			content := fmt.Sprintf("Page %s", id)
 			return protocol.NewReadResourceResult().AddContent(protocol.NewTextResourceContents(content, uri)), nil
 		},
 	})
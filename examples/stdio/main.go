@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/cfichtmueller/gomcp"
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+func main() {
+	server := gomcp.NewServer("stdio", "", "1.0.0")
+
+	server.AddTool(&gomcp.Tool{
+		Name:        "hello",
+		Title:       "Hello World",
+		Description: "This is a tool that says hello world",
+		InputSchema: protocol.NewInputSchema().
+			SetProperty("name", protocol.NewStringProperty("The name to say hello to")).
+			SetRequired("name"),
+		Handler: func(ctx context.Context, arguments *gomcp.ToolArguments) (*protocol.CallToolsResult, error) {
+			name, err := arguments.String("name")
+			if err != nil {
+				return nil, err
+			}
+			content := protocol.NewTextContent().SetText(fmt.Sprintf("Hello, %s!", name))
+			return protocol.NewCallToolsResult().AddContent(content), nil
+		},
+	})
+
+	// stdout carries the protocol, so logs have to go to stderr.
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := gomcp.NewStdioTransport(server).Run(ctx); err != nil && err != context.Canceled {
+		slog.Error("Server stopped", "error", err)
+		os.Exit(1)
+	}
+}
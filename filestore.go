@@ -0,0 +1,159 @@
+package gomcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileSessionStore keeps each session as a JSON file in a directory. The modification time of
+// a file marks the last activity of its session. Replicas sharing the directory, e.g. on a
+// network file system, share their sessions.
+type FileSessionStore struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewFileSessionStore creates a store in dir, creating the directory if necessary.
+func NewFileSessionStore(dir string, ttl time.Duration) (*FileSessionStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileSessionStore{
+		dir: dir,
+		ttl: ttl,
+	}, nil
+}
+
+func (s *FileSessionStore) Create(ctx context.Context, session *Session) error {
+	s.removeExpired()
+	return s.write(session)
+}
+
+func (s *FileSessionStore) Get(ctx context.Context, id string) (*Session, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkExpiry(path); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var session Session
+	if err := json.Unmarshal(b, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *FileSessionStore) Update(ctx context.Context, session *Session) error {
+	path, err := s.path(session.Id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkExpiry(path); err != nil {
+		return err
+	}
+	return s.write(session)
+}
+
+func (s *FileSessionStore) Touch(ctx context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkExpiry(path); err != nil {
+		return err
+	}
+	now := time.Now()
+	return os.Chtimes(path, now, now)
+}
+
+func (s *FileSessionStore) Delete(ctx context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// checkExpiry returns ErrSessionNotFound if the session file does not exist or has expired, in
+// which case it is removed.
+func (s *FileSessionStore) checkExpiry(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if time.Since(info.ModTime()) > s.ttl {
+		os.Remove(path)
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// removeExpired removes the files of expired sessions. Clients that leave without deleting their
+// session never look it up again, so expired sessions are not only removed on access.
+func (s *FileSessionStore) removeExpired() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || !validSessionId(id) {
+			continue
+		}
+		s.checkExpiry(filepath.Join(s.dir, entry.Name()))
+	}
+}
+
+func (s *FileSessionStore) write(session *Session) error {
+	path, err := s.path(session.Id)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(s.dir, ".session-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// path returns the file of a session. Since ids are received from clients, anything that is not
+// a valid session id is rejected to keep paths inside the store directory.
+func (s *FileSessionStore) path(id string) (string, error) {
+	if !validSessionId(id) {
+		return "", ErrSessionNotFound
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
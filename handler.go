@@ -9,6 +9,16 @@ type HandlerResponse struct {
 	Status   int
 	SendBody bool
 	Body     *JsonRpcResponse
+	// Batch holds the responses to a batch of messages. It is sent instead of Body if it is set.
+	Batch []*JsonRpcResponse
+}
+
+// payload returns the message to send to the client.
+func (r *HandlerResponse) payload() any {
+	if r.Batch != nil {
+		return r.Batch
+	}
+	return r.Body
 }
 
 func RequestResponse(body *JsonRpcResponse) *HandlerResponse {
@@ -35,4 +45,12 @@ func BadRequestResponse(body *JsonRpcResponse) *HandlerResponse {
 	}
 }
 
+func BatchResponse(bodies []*JsonRpcResponse) *HandlerResponse {
+	return &HandlerResponse{
+		Status:   http.StatusOK,
+		SendBody: true,
+		Batch:    bodies,
+	}
+}
+
 type HandleFunc func(ctx context.Context, message *JsonRpcRequest) *HandlerResponse
@@ -1,15 +1,54 @@
 package gomcp
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
+	"regexp"
 )
 
+// JsonRpcRequest is a message exchanged with a client. Besides requests and notifications, it
+// represents the responses of the client to requests sent by the server, in which case Result
+// or Error is set instead of Method.
 type JsonRpcRequest struct {
 	Jsonrpc string          `json:"jsonrpc"`
-	Method  string          `json:"method"`
-	Params  json.RawMessage `json:"params"`
-	Id      any             `json:"id"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *JsonRpcError   `json:"error,omitempty"`
+	// Id is kept as received, so that it is echoed back exactly. It is nil if the message has no
+	// id, and the JSON literal null if the id is null.
+	Id json.RawMessage `json:"id,omitempty"`
+}
+
+// IsRequest reports whether the message is a request that expects a response.
+func (r *JsonRpcRequest) IsRequest() bool {
+	return r.Method != "" && r.Id != nil
+}
+
+// IsNotification reports whether the message is a notification, which must not be answered.
+func (r *JsonRpcRequest) IsNotification() bool {
+	return r.Method != "" && r.Id == nil
+}
+
+// IsResponse reports whether the message is a response to a request sent by the server.
+func (r *JsonRpcRequest) IsResponse() bool {
+	return r.Method == "" && r.Id != nil
+}
+
+// integerRegexp matches JSON integer literals.
+var integerRegexp = regexp.MustCompile(`^-?(?:0|[1-9][0-9]*)$`)
+
+// validId reports whether id is a string or an integer. Unlike in JSON-RPC, request ids must not
+// be null or fractional numbers in MCP.
+func validId(id json.RawMessage) bool {
+	id = bytes.TrimSpace(id)
+	if len(id) > 0 && id[0] == '"' {
+		var s string
+		return json.Unmarshal(id, &s) == nil
+	}
+	return integerRegexp.Match(id)
 }
 
 func ReadJsonRpcRequest(r io.Reader) (*JsonRpcRequest, error) {
@@ -18,6 +57,41 @@ func ReadJsonRpcRequest(r io.Reader) (*JsonRpcRequest, error) {
 	return &request, err
 }
 
+// ReadJsonRpcMessage reads a single message or a batch of messages. The entries of a batch are
+// returned undecoded, since invalid entries are answered individually. Errors are *Error with
+// ErrorCodeParseError if the input is not JSON, or ErrorCodeInvalidRequest if it is JSON but not
+// a message or a batch.
+func ReadJsonRpcMessage(r io.Reader) (*JsonRpcRequest, []json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, nil, NewError(ErrorCodeParseError, "Parse error")
+	}
+	switch bytes.TrimLeft(raw, " \t\r\n")[0] {
+	case '[':
+		batch := make([]json.RawMessage, 0)
+		if err := json.Unmarshal(raw, &batch); err != nil {
+			return nil, nil, NewError(ErrorCodeInvalidRequest, "Invalid request")
+		}
+		return nil, batch, nil
+	case '{':
+		var request JsonRpcRequest
+		if err := json.Unmarshal(raw, &request); err != nil {
+			return nil, nil, NewError(ErrorCodeInvalidRequest, "Invalid request")
+		}
+		return &request, nil, nil
+	}
+	return nil, nil, NewError(ErrorCodeInvalidRequest, "Invalid request")
+}
+
+// readError returns the error to answer a failed ReadJsonRpcMessage with.
+func readError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return NewError(ErrorCodeParseError, "Parse error")
+}
+
 type JsonRpcResponse struct {
 	Jsonrpc string        `json:"jsonrpc"`
 	Result  any           `json:"result,omitempty"`
@@ -45,8 +119,16 @@ func (r *JsonRpcResponse) Write(w io.Writer) error {
 	return json.NewEncoder(w).Encode(r)
 }
 
-type JsonRpcError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    any    `json:"data,omitempty"`
+type JsonRpcNotification struct {
+	Jsonrpc string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+func NewJsonRpcNotification(method string, params any) *JsonRpcNotification {
+	return &JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  method,
+		Params:  params,
+	}
 }
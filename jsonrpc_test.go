@@ -0,0 +1,51 @@
+package gomcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidId(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{`1`, true},
+		{`0`, true},
+		{`-12`, true},
+		{`"abc"`, true},
+		{`""`, true},
+		{`"a\"b"`, true},
+		{`1.5`, false},
+		{`1e3`, false},
+		{`-0.0`, false},
+		{`01`, false},
+		{`-`, false},
+		{`null`, false},
+		{`true`, false},
+		{`{}`, false},
+		{`[1]`, false},
+		{`"unterminated`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if valid := validId(json.RawMessage(tt.id)); valid != tt.valid {
+				t.Fatalf("expected %v, got %v", tt.valid, valid)
+			}
+		})
+	}
+}
+
+func TestServerRejectsFractionalIds(t *testing.T) {
+	res := newTestServer().handle(t.Context(), &JsonRpcRequest{
+		Jsonrpc: "2.0",
+		Method:  "ping",
+		Id:      json.RawMessage(`1.5`),
+	})
+	response := decodeJson[map[string]any](t, res.payload())
+	e, _ := response["error"].(map[string]any)
+	if e == nil || e["code"] != float64(ErrorCodeInvalidRequest) || response["id"] != nil {
+		t.Fatalf("expected an invalid request error with a null id, got %v", response)
+	}
+}
@@ -0,0 +1,137 @@
+package gomcp
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// defaultLogLevel is the minimum level of messages sent to clients that did not set a level.
+const defaultLogLevel = protocol.LoggingLevelInfo
+
+// Logger returns a logger that sends its records to the client whose request is handled in ctx
+// as notifications/message. Records below the level the client set with logging/setLevel are
+// dropped, as are all records if there is no channel to the client.
+//
+// The record message and attributes are sent as a JSON object in the data field.
+func Logger(ctx context.Context) *slog.Logger {
+	return slog.New(&clientLogHandler{ctx: ctx})
+}
+
+type clientLogHandler struct {
+	ctx    context.Context
+	attrs  []groupedAttr
+	groups []string
+}
+
+// groupedAttr is an attribute together with the groups that were open when it was added.
+type groupedAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+func (h *clientLogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := defaultLogLevel
+	if session := sessionFromContext(h.ctx); session != nil {
+		if l := session.logLevel(); l != "" {
+			minLevel = l
+		}
+	}
+	return loggingLevel(level).AtLeast(minLevel)
+}
+
+func (h *clientLogHandler) Handle(_ context.Context, record slog.Record) error {
+	data := map[string]any{
+		"message": record.Message,
+	}
+	for _, a := range h.attrs {
+		addAttr(group(data, a.groups), a.attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(group(data, h.groups), attr)
+		return true
+	})
+
+	params := &protocol.LoggingMessageNotificationParams{
+		Data:  data,
+		Level: loggingLevel(record.Level),
+	}
+	return Notify(h.ctx, "notifications/message", params)
+}
+
+func (h *clientLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := &clientLogHandler{
+		ctx:    h.ctx,
+		attrs:  h.attrs[:len(h.attrs):len(h.attrs)],
+		groups: h.groups,
+	}
+	for _, attr := range attrs {
+		next.attrs = append(next.attrs, groupedAttr{groups: h.groups, attr: attr})
+	}
+	return next
+}
+
+func (h *clientLogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &clientLogHandler{
+		ctx:    h.ctx,
+		attrs:  h.attrs,
+		groups: append(h.groups[:len(h.groups):len(h.groups)], name),
+	}
+}
+
+// group returns the map nested in m under the given groups, creating it if necessary.
+func group(m map[string]any, groups []string) map[string]any {
+	for _, name := range groups {
+		g, ok := m[name].(map[string]any)
+		if !ok {
+			g = make(map[string]any)
+			m[name] = g
+		}
+		m = g
+	}
+	return m
+}
+
+func addAttr(m map[string]any, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() != slog.KindGroup {
+		if attr.Key != "" {
+			m[attr.Key] = value.Any()
+		}
+		return
+	}
+	target := m
+	if attr.Key != "" {
+		target = group(m, []string{attr.Key})
+	}
+	for _, a := range value.Group() {
+		addAttr(target, a)
+	}
+}
+
+// loggingLevel maps a slog level to a syslog severity. The levels between and above the slog
+// levels are used for notice, critical, alert and emergency.
+func loggingLevel(level slog.Level) protocol.LoggingLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return protocol.LoggingLevelDebug
+	case level < slog.LevelInfo+2:
+		return protocol.LoggingLevelInfo
+	case level < slog.LevelWarn:
+		return protocol.LoggingLevelNotice
+	case level < slog.LevelError:
+		return protocol.LoggingLevelWarning
+	case level < slog.LevelError+4:
+		return protocol.LoggingLevelError
+	case level < slog.LevelError+8:
+		return protocol.LoggingLevelCritical
+	case level < slog.LevelError+12:
+		return protocol.LoggingLevelAlert
+	default:
+		return protocol.LoggingLevelEmergency
+	}
+}
@@ -0,0 +1,53 @@
+package gomcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// cursor is the position of a page in a list. It is handed to clients as an opaque string.
+type cursor struct {
+	Offset int `json:"o,omitempty"`
+	// Provider is the 1-based index of the resource provider whose resources are listed. Zero
+	// refers to the statically registered resources.
+	Provider int `json:"p,omitempty"`
+	// ProviderCursor is the cursor returned by the resource provider.
+	ProviderCursor string `json:"c,omitempty"`
+}
+
+func (c cursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	if s == "" {
+		return c, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errInvalidCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil || c.Offset < 0 || c.Provider < 0 {
+		return c, errInvalidCursor
+	}
+	return c, nil
+}
+
+// page returns the page of items starting at the cursor, and the cursor of the next page. The
+// next cursor is empty if this is the last page. A size of zero or less returns all remaining
+// items.
+func page[T any](items []T, c cursor, size int) ([]T, string) {
+	if c.Offset >= len(items) {
+		return nil, ""
+	}
+	items = items[c.Offset:]
+	if size <= 0 || len(items) <= size {
+		return items, ""
+	}
+	return items[:size], cursor{Offset: c.Offset + size}.encode()
+}
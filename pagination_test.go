@@ -0,0 +1,108 @@
+package gomcp
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// sliceProvider lists uris in pages of pageSize, using offsets as cursors.
+type sliceProvider struct {
+	uris     []string
+	pageSize int
+}
+
+func (p *sliceProvider) List(ctx context.Context, cursor string) ([]*protocol.Resource, string, error) {
+	offset := 0
+	if cursor != "" {
+		offset, _ = strconv.Atoi(cursor)
+	}
+	end := min(offset+p.pageSize, len(p.uris))
+	resources := make([]*protocol.Resource, 0)
+	for _, uri := range p.uris[offset:end] {
+		resources = append(resources, &protocol.Resource{Name: uri, Uri: uri})
+	}
+	if end == len(p.uris) {
+		return resources, "", nil
+	}
+	return resources, strconv.Itoa(end), nil
+}
+
+func (p *sliceProvider) Read(ctx context.Context, uri string) (*protocol.ReadResourceResult, error) {
+	return nil, ErrNoSuchResource
+}
+
+func TestListResourcesPagesAcrossProviders(t *testing.T) {
+	server := newTestServer()
+	server.SetPageSize(2)
+	for _, uri := range []string{"static://1", "static://2", "static://3"} {
+		server.AddResource(&Resource{Name: uri, Uri: uri})
+	}
+	server.AddResourceProvider(&sliceProvider{uris: []string{"a://1", "a://2", "a://3"}, pageSize: 2})
+	server.AddResourceProvider(&sliceProvider{pageSize: 2})
+	server.AddResourceProvider(&sliceProvider{uris: []string{"c://1"}, pageSize: 2})
+
+	var uris []string
+	var pages [][]string
+	cursor := ""
+	for range 10 {
+		params := `{}`
+		if cursor != "" {
+			params = `{"cursor":"` + cursor + `"}`
+		}
+		response := call(t, server, "resources/list", params)
+		result, ok := response["result"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected a result, got %v", response)
+		}
+		var page []string
+		for _, resource := range result["resources"].([]any) {
+			page = append(page, resource.(map[string]any)["uri"].(string))
+		}
+		pages = append(pages, page)
+		uris = append(uris, page...)
+		cursor, _ = result["nextCursor"].(string)
+		if cursor == "" {
+			break
+		}
+	}
+
+	want := []string{"static://1", "static://2", "static://3", "a://1", "a://2", "a://3", "c://1"}
+	if !slices.Equal(uris, want) {
+		t.Fatalf("expected %v, got %v in pages %v", want, uris, pages)
+	}
+	if cursor != "" {
+		t.Fatalf("expected the last page to have no cursor, pages %v", pages)
+	}
+	// Static resources and the resources of different providers are never mixed on a page.
+	for _, page := range pages {
+		for _, uri := range page {
+			if scheme(uri) != scheme(page[0]) {
+				t.Errorf("page %v mixes sources", page)
+				break
+			}
+		}
+	}
+}
+
+func scheme(uri string) string {
+	scheme, _, _ := strings.Cut(uri, "://")
+	return scheme
+}
+
+func TestListResourcesRejectsInvalidCursors(t *testing.T) {
+	server := newTestServer()
+	server.AddResourceProvider(&sliceProvider{pageSize: 2})
+
+	for _, c := range []string{"not base64!", cursor{Provider: 2}.encode(), cursor{Offset: -1}.encode()} {
+		response := call(t, server, "resources/list", `{"cursor":"`+c+`"}`)
+		e, _ := response["error"].(map[string]any)
+		if e == nil || e["code"] != float64(ErrorCodeInvalidParams) {
+			t.Errorf("cursor %q: expected an invalid params error, got %v", c, response)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package gomcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+type progressTokenKey struct{}
+
+func withProgressToken(ctx context.Context, token json.RawMessage) context.Context {
+	return context.WithValue(ctx, progressTokenKey{}, token)
+}
+
+// ReportProgress sends a notifications/progress for the request handled in ctx. A total of zero
+// or less means the total is unknown, an empty message is omitted.
+//
+// If the client did not request progress for the request, the report is silently dropped.
+func ReportProgress(ctx context.Context, progress, total float64, message string) error {
+	token, _ := ctx.Value(progressTokenKey{}).(json.RawMessage)
+	if len(token) == 0 || string(token) == "null" {
+		return nil
+	}
+	params := protocol.NewProgressNotificationParams(token, progress).SetMessage(message)
+	if total > 0 {
+		params.SetTotal(total)
+	}
+	return Notify(ctx, "notifications/progress", params)
+}
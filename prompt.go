@@ -0,0 +1,27 @@
+package gomcp
+
+import (
+	"context"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+type Prompt struct {
+	Name        string
+	Title       string
+	Description string
+	Arguments   []*PromptArgument
+	// Handler renders the prompt. Errors are sent to the client as JSON-RPC errors; return an
+	// *Error to control the code and data.
+	Handler func(ctx context.Context, arguments map[string]string) (*protocol.GetPromptResult, error)
+}
+
+type PromptArgument struct {
+	Name        string
+	Title       string
+	Description string
+	Required    bool
+	// Complete returns completion values for the argument. If it is not set, no values are
+	// offered.
+	Complete CompleteFunc
+}
@@ -0,0 +1,12 @@
+package protocol
+
+import "encoding/json"
+
+// CancelledNotificationParams are the params of a notifications/cancelled notification, sent to
+// indicate that a previously-issued request is cancelled.
+type CancelledNotificationParams struct {
+	// An optional string describing the reason for the cancellation.
+	Reason string `json:"reason,omitempty"`
+	// The ID of the request to cancel.
+	RequestId json.RawMessage `json:"requestId"`
+}
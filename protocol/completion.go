@@ -0,0 +1,72 @@
+package protocol
+
+// CompleteParams are the params of a completion/complete request, asking the server for
+// completion options of a prompt argument or resource template variable.
+type CompleteParams struct {
+	// The argument's information.
+	Argument CompleteArgument `json:"argument"`
+	// Additional, optional context for completions.
+	Context *CompleteContext  `json:"context,omitempty"`
+	Ref     CompleteReference `json:"ref"`
+}
+
+type CompleteArgument struct {
+	// The name of the argument.
+	Name string `json:"name"`
+	// The value of the argument to use for completion matching.
+	Value string `json:"value"`
+}
+
+type CompleteContext struct {
+	// Previously-resolved variables in a URI template or prompt.
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+const (
+	ReferenceTypePrompt   = "ref/prompt"
+	ReferenceTypeResource = "ref/resource"
+)
+
+// CompleteReference identifies a prompt by its name or a resource template by its URI template.
+type CompleteReference struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	Uri  string `json:"uri,omitempty"`
+}
+
+// CompleteResult is the server’s response to a completion/complete request.
+type CompleteResult struct {
+	Completion *Completion `json:"completion"`
+}
+
+type Completion struct {
+	// Indicates whether there are additional completion options beyond those provided in the current response, even if the exact total is unknown.
+	HasMore bool `json:"hasMore,omitempty"`
+	// The total number of completion options available. This can exceed the number of values actually sent in the response.
+	Total *int `json:"total,omitempty"`
+	// An array of completion values. Must not exceed 100 items.
+	Values []string `json:"values"`
+}
+
+// MaxCompletionValues is the maximum number of values in a completion.
+const MaxCompletionValues = 100
+
+// NewCompleteResult creates a result with the given values. If there are more than
+// MaxCompletionValues, the values are truncated and the result indicates that there are more.
+func NewCompleteResult(values []string) *CompleteResult {
+	if values == nil {
+		values = make([]string, 0)
+	}
+	total := len(values)
+	completion := &Completion{
+		Total:  &total,
+		Values: values,
+	}
+	if total > MaxCompletionValues {
+		completion.Values = values[:MaxCompletionValues]
+		completion.HasMore = true
+	}
+	return &CompleteResult{
+		Completion: completion,
+	}
+}
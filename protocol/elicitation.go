@@ -0,0 +1,91 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/cfichtmueller/gomcp/schema"
+)
+
+// ElicitationCapability is present if the client supports elicitation from the user.
+type ElicitationCapability struct{}
+
+// ElicitParams are the params of an elicitation/create request, asking the client to elicit
+// additional information from the user.
+type ElicitParams struct {
+	// The message to present to the user.
+	Message string `json:"message"`
+	// A restricted subset of JSON Schema. Only top-level properties are allowed, without nesting.
+	RequestedSchema *ElicitRequestSchema `json:"requestedSchema"`
+}
+
+func NewElicitParams(message string, requestedSchema *ElicitRequestSchema) *ElicitParams {
+	return &ElicitParams{
+		Message:         message,
+		RequestedSchema: requestedSchema,
+	}
+}
+
+// ElicitRequestSchema is the schema of the information requested from the user. Its properties
+// must be primitive schemas, see NewStringProperty, NewNumberProperty, NewIntegerProperty,
+// NewBooleanProperty and NewEnumProperty.
+type ElicitRequestSchema struct {
+	Properties schema.M `json:"properties"`
+	Required   []string `json:"required,omitempty"`
+	Type       string   `json:"type"`
+}
+
+func NewElicitRequestSchema() *ElicitRequestSchema {
+	return &ElicitRequestSchema{
+		Properties: make(schema.M),
+		Type:       "object",
+	}
+}
+
+func (s *ElicitRequestSchema) SetProperty(key string, value schema.M) *ElicitRequestSchema {
+	if s.Properties == nil {
+		s.Properties = make(schema.M)
+	}
+	s.Properties[key] = value
+	return s
+}
+
+func (s *ElicitRequestSchema) SetRequired(required ...string) *ElicitRequestSchema {
+	s.Required = required
+	return s
+}
+
+const (
+	ElicitActionAccept  = "accept"
+	ElicitActionDecline = "decline"
+	ElicitActionCancel  = "cancel"
+)
+
+// ErrElicitationNotAccepted is returned when decoding the content of an elicitation the user
+// declined or cancelled.
+var ErrElicitationNotAccepted = errors.New("elicitation was not accepted")
+
+// ElicitResult is the client’s response to an elicitation/create request.
+type ElicitResult struct {
+	// The user action in response to the elicitation: accept, decline or cancel.
+	Action string `json:"action"`
+	// The submitted form data, only present when action is accept.
+	Content json.RawMessage `json:"content,omitempty"`
+}
+
+// Accepted reports whether the user submitted the requested information.
+func (r *ElicitResult) Accepted() bool {
+	return r.Action == ElicitActionAccept
+}
+
+// Decode decodes the submitted form data into v. It returns ErrElicitationNotAccepted if the
+// user did not accept the elicitation.
+func (r *ElicitResult) Decode(v any) error {
+	if !r.Accepted() {
+		return ErrElicitationNotAccepted
+	}
+	if len(r.Content) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Content, v)
+}
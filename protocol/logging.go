@@ -0,0 +1,53 @@
+package protocol
+
+// LoggingLevel is the severity of a log message. These map to syslog message severities, as
+// specified in RFC-5424.
+type LoggingLevel string
+
+const (
+	LoggingLevelDebug     LoggingLevel = "debug"
+	LoggingLevelInfo      LoggingLevel = "info"
+	LoggingLevelNotice    LoggingLevel = "notice"
+	LoggingLevelWarning   LoggingLevel = "warning"
+	LoggingLevelError     LoggingLevel = "error"
+	LoggingLevelCritical  LoggingLevel = "critical"
+	LoggingLevelAlert     LoggingLevel = "alert"
+	LoggingLevelEmergency LoggingLevel = "emergency"
+)
+
+var loggingLevelSeverities = map[LoggingLevel]int{
+	LoggingLevelDebug:     0,
+	LoggingLevelInfo:      1,
+	LoggingLevelNotice:    2,
+	LoggingLevelWarning:   3,
+	LoggingLevelError:     4,
+	LoggingLevelCritical:  5,
+	LoggingLevelAlert:     6,
+	LoggingLevelEmergency: 7,
+}
+
+// Valid reports whether l is one of the defined levels.
+func (l LoggingLevel) Valid() bool {
+	_, ok := loggingLevelSeverities[l]
+	return ok
+}
+
+// AtLeast reports whether l is as severe as or more severe than other.
+func (l LoggingLevel) AtLeast(other LoggingLevel) bool {
+	return loggingLevelSeverities[l] >= loggingLevelSeverities[other]
+}
+
+type LoggingSetLevelParams struct {
+	// The level of logging that the client wants to receive from the server. The server should send all logs at this level and higher (i.e., more severe) to the client as notifications/message.
+	Level LoggingLevel `json:"level"`
+}
+
+// LoggingMessageNotificationParams are the params of a notifications/message notification.
+type LoggingMessageNotificationParams struct {
+	// The data to be logged, such as a string message or an object. Any JSON serializable type is allowed here.
+	Data any `json:"data"`
+	// The severity of this log message.
+	Level LoggingLevel `json:"level"`
+	// An optional name of the logger issuing this message.
+	Logger string `json:"logger,omitempty"`
+}
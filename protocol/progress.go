@@ -0,0 +1,40 @@
+package protocol
+
+import "encoding/json"
+
+// RequestMeta is the _meta object of request params.
+type RequestMeta struct {
+	// If specified, the caller is requesting out-of-band progress notifications for this request.
+	// The value is kept as received, since it is echoed back in every notification.
+	ProgressToken json.RawMessage `json:"progressToken,omitempty"`
+}
+
+// ProgressNotificationParams are the params of a notifications/progress notification, informing
+// the receiver of a progress update for a long-running request.
+type ProgressNotificationParams struct {
+	// An optional message describing the current progress.
+	Message string `json:"message,omitempty"`
+	// The progress thus far. This should increase every time progress is made, even if the total is unknown.
+	Progress float64 `json:"progress"`
+	// The progress token which was given in the initial request.
+	ProgressToken json.RawMessage `json:"progressToken"`
+	// Total number of items to process (or total progress required), if known.
+	Total *float64 `json:"total,omitempty"`
+}
+
+func NewProgressNotificationParams(progressToken json.RawMessage, progress float64) *ProgressNotificationParams {
+	return &ProgressNotificationParams{
+		Progress:      progress,
+		ProgressToken: progressToken,
+	}
+}
+
+func (p *ProgressNotificationParams) SetTotal(total float64) *ProgressNotificationParams {
+	p.Total = &total
+	return p
+}
+
+func (p *ProgressNotificationParams) SetMessage(message string) *ProgressNotificationParams {
+	p.Message = message
+	return p
+}
@@ -0,0 +1,92 @@
+package protocol
+
+// Prompt is a prompt or prompt template that the server offers.
+type Prompt struct {
+	// A list of arguments to use for templating the prompt.
+	Arguments []*PromptArgument `json:"arguments,omitempty"`
+	// An optional description of what this prompt provides.
+	Description string `json:"description,omitempty"`
+	// Intended for programmatic or logical use, but used as a display name in past specs or fallback (if title isn’t present).
+	Name string `json:"name"`
+	// Intended for UI and end-user contexts — optimized to be human-readable and easily understood, even by those unfamiliar with domain-specific terminology.
+	Title string `json:"title,omitempty"`
+}
+
+// PromptArgument describes an argument that a prompt can accept.
+type PromptArgument struct {
+	// A human-readable description of the argument.
+	Description string `json:"description,omitempty"`
+	Name        string `json:"name"`
+	// Whether this argument must be provided.
+	Required bool   `json:"required,omitempty"`
+	Title    string `json:"title,omitempty"`
+}
+
+type ListPromptsRequest struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// ListPromptsResult is the server’s response to a prompts/list request from the client.
+type ListPromptsResult struct {
+	NextCursor string    `json:"nextCursor,omitempty"`
+	Prompts    []*Prompt `json:"prompts"`
+}
+
+func NewListPromptsResult() *ListPromptsResult {
+	return &ListPromptsResult{
+		Prompts: make([]*Prompt, 0),
+	}
+}
+
+func (r *ListPromptsResult) SetNextCursor(nextCursor string) *ListPromptsResult {
+	r.NextCursor = nextCursor
+	return r
+}
+
+func (r *ListPromptsResult) AddPrompt(prompt *Prompt) *ListPromptsResult {
+	r.Prompts = append(r.Prompts, prompt)
+	return r
+}
+
+type GetPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments"`
+}
+
+// GetPromptResult is the server’s response to a prompts/get request from the client.
+type GetPromptResult struct {
+	// An optional description for the prompt.
+	Description string           `json:"description,omitempty"`
+	Messages    []*PromptMessage `json:"messages"`
+}
+
+func NewGetPromptResult() *GetPromptResult {
+	return &GetPromptResult{
+		Messages: make([]*PromptMessage, 0),
+	}
+}
+
+func (r *GetPromptResult) SetDescription(description string) *GetPromptResult {
+	r.Description = description
+	return r
+}
+
+func (r *GetPromptResult) AddMessage(message *PromptMessage) *GetPromptResult {
+	r.Messages = append(r.Messages, message)
+	return r
+}
+
+// PromptMessage describes a message returned as part of a prompt.
+//
+// The content is a TextContent, ImageContent, AudioContent, ResourceLink or EmbeddedResource.
+type PromptMessage struct {
+	Content any  `json:"content"`
+	Role    Role `json:"role"`
+}
+
+func NewPromptMessage(role Role, content any) *PromptMessage {
+	return &PromptMessage{
+		Content: content,
+		Role:    role,
+	}
+}
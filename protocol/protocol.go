@@ -6,13 +6,23 @@ type InitializeParams struct {
 	ClientInfo      *ClientInfo         `json:"clientInfo"`
 }
 
+// ClientCapabilities are the capabilities a client may support. Known capabilities are defined
+// here, but this is not a closed set: any client can define its own, additional capabilities.
 type ClientCapabilities struct {
-	Methods map[string]any `json:"methods"`
+	Elicitation *ElicitationCapability `json:"elicitation,omitempty"`
+	// Experimental, non-standard capabilities that the client supports.
+	Experimental map[string]any      `json:"experimental,omitempty"`
+	Roots        *RootsCapability    `json:"roots,omitempty"`
+	Sampling     *SamplingCapability `json:"sampling,omitempty"`
+}
+
+func NewClientCapabilities() *ClientCapabilities {
+	return &ClientCapabilities{}
 }
 
 type ClientInfo struct {
 	Name    string `json:"name"`
-	Title   string `json:"title"`
+	Title   string `json:"title,omitempty"`
 	Version string `json:"version"`
 }
 
@@ -24,10 +34,11 @@ type InitializeResult struct {
 }
 
 type ServerCapabilities struct {
-	Logging   *Capability `json:"logging,omitempty"`
-	Prompts   *Capability `json:"prompts,omitempty"`
-	Resources *Capability `json:"resources,omitempty"`
-	Tools     *Capability `json:"tools,omitempty"`
+	Completions *Capability `json:"completions,omitempty"`
+	Logging     *Capability `json:"logging,omitempty"`
+	Prompts     *Capability `json:"prompts,omitempty"`
+	Resources   *Capability `json:"resources,omitempty"`
+	Tools       *Capability `json:"tools,omitempty"`
 }
 
 func NewServerCapabilities() *ServerCapabilities {
@@ -46,8 +57,9 @@ func NewCapability() *Capability {
 	}
 }
 
-func (c *Capability) SetSubscribed(subscribed bool) {
+func (c *Capability) SetSubscribed(subscribed bool) *Capability {
 	c.Subscribed = &subscribed
+	return c
 }
 
 func (c *Capability) SetListChanged(listChanged bool) *Capability {
@@ -57,10 +69,6 @@ func (c *Capability) SetListChanged(listChanged bool) *Capability {
 
 type ServerInfo struct {
 	Name    string `json:"name"`
-	Title   string `json:"title"`
+	Title   string `json:"title,omitempty"`
 	Version string `json:"version"`
 }
-
-type LoggingSetLevelParams struct {
-	Level string `json:"level"`
-}
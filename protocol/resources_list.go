@@ -2,11 +2,15 @@ package protocol
 
 // ListResourcesRequest is sent from the client to request a list of resources the server has.
 type ListResourcesRequest struct {
+	// An opaque token representing the current pagination position. If provided, the server should return results starting after this cursor.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 // ListResourcesResult is server’s response to a resources/list request from the client.
 type ListResourcesResult struct {
-	Resources []*Resource `json:"resources"`
+	// An opaque token representing the pagination position after the last returned result. If present, there may be more results available.
+	NextCursor string      `json:"nextCursor,omitempty"`
+	Resources  []*Resource `json:"resources"`
 }
 
 // NewListResourcesResult creates a new ListResourcesResult with an empty list of resources.
@@ -16,12 +20,23 @@ func NewListResourcesResult() *ListResourcesResult {
 	}
 }
 
+func (r *ListResourcesResult) SetNextCursor(nextCursor string) *ListResourcesResult {
+	r.NextCursor = nextCursor
+	return r
+}
+
 func (r *ListResourcesResult) AddResource(resource *Resource) *ListResourcesResult {
 	r.Resources = append(r.Resources, resource)
 	return r
 }
 
+// ListResourcesTemplatesRequest is sent from the client to request a list of resource templates the server has.
+type ListResourcesTemplatesRequest struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
 type ListResourcesTemplatesResult struct {
+	NextCursor        string              `json:"nextCursor,omitempty"`
 	ResourceTemplates []*ResourceTemplate `json:"resourceTemplates"`
 }
 
@@ -31,6 +46,11 @@ func NewListResourcesTemplatesResult() *ListResourcesTemplatesResult {
 	}
 }
 
+func (r *ListResourcesTemplatesResult) SetNextCursor(nextCursor string) *ListResourcesTemplatesResult {
+	r.NextCursor = nextCursor
+	return r
+}
+
 func (r *ListResourcesTemplatesResult) AddResourceTemplate(resourceTemplate *ResourceTemplate) *ListResourcesTemplatesResult {
 	r.ResourceTemplates = append(r.ResourceTemplates, resourceTemplate)
 	return r
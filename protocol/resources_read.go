@@ -24,3 +24,20 @@ func (r *ReadResourceResult) AddContent(content any) *ReadResourceResult {
 	r.Contents = append(r.Contents, content)
 	return r
 }
+
+type SubscribeParams struct {
+	// The URI of the resource to subscribe to.
+	Uri string `json:"uri"`
+}
+
+type UnsubscribeParams struct {
+	// The URI of the resource to unsubscribe from.
+	Uri string `json:"uri"`
+}
+
+// ResourceUpdatedNotificationParams are the params of a notifications/resources/updated
+// notification, informing the client that a resource it subscribed to has changed.
+type ResourceUpdatedNotificationParams struct {
+	// The URI of the resource that has been updated. This might be a sub-resource of the one that the client actually subscribed to.
+	Uri string `json:"uri"`
+}
@@ -0,0 +1,20 @@
+package protocol
+
+// RootsCapability is present if the client supports listing roots.
+type RootsCapability struct {
+	// Whether the client supports notifications for changes to the roots list.
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// Root is a root directory or file that the server can operate on.
+type Root struct {
+	// An optional name for the root.
+	Name string `json:"name,omitempty"`
+	// The URI identifying the root. This must start with file:// for now.
+	Uri string `json:"uri"`
+}
+
+// ListRootsResult is the client’s response to a roots/list request from the server.
+type ListRootsResult struct {
+	Roots []*Root `json:"roots"`
+}
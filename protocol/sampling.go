@@ -0,0 +1,151 @@
+package protocol
+
+// SamplingCapability is present if the client supports sampling from an LLM.
+type SamplingCapability struct{}
+
+// CreateMessageParams are the params of a sampling/createMessage request, asking the client to
+// sample an LLM.
+type CreateMessageParams struct {
+	// A request to include context from one or more MCP servers (including the caller), to be attached to the prompt. The client MAY ignore this request.
+	IncludeContext string `json:"includeContext,omitempty"`
+	// The maximum number of tokens to sample, as requested by the server. The client MAY choose to sample fewer tokens than requested.
+	MaxTokens int                `json:"maxTokens"`
+	Messages  []*SamplingMessage `json:"messages"`
+	// Optional metadata to pass through to the LLM provider. The format of this metadata is provider-specific.
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// The server’s preferences for which model to select. The client MAY ignore these preferences.
+	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"`
+	StopSequences    []string          `json:"stopSequences,omitempty"`
+	// An optional system prompt the server wants to use for sampling. The client MAY modify or omit this prompt.
+	SystemPrompt string   `json:"systemPrompt,omitempty"`
+	Temperature  *float64 `json:"temperature,omitempty"`
+}
+
+const (
+	IncludeContextNone       = "none"
+	IncludeContextThisServer = "thisServer"
+	IncludeContextAllServers = "allServers"
+)
+
+func NewCreateMessageParams(maxTokens int) *CreateMessageParams {
+	return &CreateMessageParams{
+		MaxTokens: maxTokens,
+		Messages:  make([]*SamplingMessage, 0),
+	}
+}
+
+func (p *CreateMessageParams) AddMessage(message *SamplingMessage) *CreateMessageParams {
+	p.Messages = append(p.Messages, message)
+	return p
+}
+
+func (p *CreateMessageParams) SetSystemPrompt(systemPrompt string) *CreateMessageParams {
+	p.SystemPrompt = systemPrompt
+	return p
+}
+
+func (p *CreateMessageParams) SetIncludeContext(includeContext string) *CreateMessageParams {
+	p.IncludeContext = includeContext
+	return p
+}
+
+func (p *CreateMessageParams) SetTemperature(temperature float64) *CreateMessageParams {
+	p.Temperature = &temperature
+	return p
+}
+
+func (p *CreateMessageParams) SetStopSequences(stopSequences ...string) *CreateMessageParams {
+	p.StopSequences = stopSequences
+	return p
+}
+
+func (p *CreateMessageParams) SetModelPreferences(modelPreferences *ModelPreferences) *CreateMessageParams {
+	p.ModelPreferences = modelPreferences
+	return p
+}
+
+func (p *CreateMessageParams) SetMetadata(metadata map[string]any) *CreateMessageParams {
+	p.Metadata = metadata
+	return p
+}
+
+// SamplingMessage describes a message issued to or received from an LLM API.
+type SamplingMessage struct {
+	// Either a TextContent, ImageContent or AudioContent.
+	Content any  `json:"content"`
+	Role    Role `json:"role"`
+}
+
+func NewSamplingMessage(role Role, content any) *SamplingMessage {
+	return &SamplingMessage{
+		Content: content,
+		Role:    role,
+	}
+}
+
+// ModelPreferences are the server’s preferences for model selection, requested of the client
+// during sampling. The priorities are values between 0 and 1.
+type ModelPreferences struct {
+	// How much to prioritize cost when selecting a model.
+	CostPriority *float64 `json:"costPriority,omitempty"`
+	// Optional hints to use for model selection. The client SHOULD evaluate them in order.
+	Hints []*ModelHint `json:"hints,omitempty"`
+	// How much to prioritize intelligence and capabilities when selecting a model.
+	IntelligencePriority *float64 `json:"intelligencePriority,omitempty"`
+	// How much to prioritize sampling speed (latency) when selecting a model.
+	SpeedPriority *float64 `json:"speedPriority,omitempty"`
+}
+
+func NewModelPreferences() *ModelPreferences {
+	return &ModelPreferences{}
+}
+
+func (p *ModelPreferences) AddHint(name string) *ModelPreferences {
+	p.Hints = append(p.Hints, &ModelHint{Name: name})
+	return p
+}
+
+func (p *ModelPreferences) SetCostPriority(priority float64) *ModelPreferences {
+	p.CostPriority = &priority
+	return p
+}
+
+func (p *ModelPreferences) SetSpeedPriority(priority float64) *ModelPreferences {
+	p.SpeedPriority = &priority
+	return p
+}
+
+func (p *ModelPreferences) SetIntelligencePriority(priority float64) *ModelPreferences {
+	p.IntelligencePriority = &priority
+	return p
+}
+
+// ModelHint is a hint to use for model selection, e.g. a full or partial model name.
+type ModelHint struct {
+	Name string `json:"name,omitempty"`
+}
+
+// CreateMessageResult is the client’s response to a sampling/createMessage request.
+type CreateMessageResult struct {
+	Content SamplingContent `json:"content"`
+	// The name of the model that generated the message.
+	Model string `json:"model"`
+	Role  Role   `json:"role"`
+	// The reason why sampling stopped, if known.
+	StopReason string `json:"stopReason,omitempty"`
+}
+
+const (
+	StopReasonEndTurn      = "endTurn"
+	StopReasonStopSequence = "stopSequence"
+	StopReasonMaxTokens    = "maxTokens"
+)
+
+// SamplingContent is the content of a sampled message. Text is set for text content, Data and
+// MimeType for image and audio content.
+type SamplingContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
@@ -1,10 +1,12 @@
 package protocol
 
 type ListToolsRequest struct {
+	Cursor string `json:"cursor,omitempty"`
 }
 
 type ListToolsResult struct {
-	Tools []*Tool `json:"tools"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+	Tools      []*Tool `json:"tools"`
 }
 
 func NewListToolsResult() *ListToolsResult {
@@ -13,6 +15,11 @@ func NewListToolsResult() *ListToolsResult {
 	}
 }
 
+func (r *ListToolsResult) SetNextCursor(nextCursor string) *ListToolsResult {
+	r.NextCursor = nextCursor
+	return r
+}
+
 func (r *ListToolsResult) AddTool(tool *Tool) *ListToolsResult {
 	r.Tools = append(r.Tools, tool)
 	return r
@@ -36,6 +36,58 @@ func (t *TextContent) SetText(text string) *TextContent {
 	return t
 }
 
+// ImageContent is an image provided to or from an LLM.
+type ImageContent struct {
+	ContentBlock
+	// The base64-encoded image data.
+	Data     string `json:"data"`
+	MimeType string `json:"mimeType"`
+}
+
+func NewImageContent(data, mimeType string) *ImageContent {
+	return &ImageContent{
+		ContentBlock: ContentBlock{
+			Type: "image",
+		},
+		Data:     data,
+		MimeType: mimeType,
+	}
+}
+
+// AudioContent is audio provided to or from an LLM.
+type AudioContent struct {
+	ContentBlock
+	// The base64-encoded audio data.
+	Data     string `json:"data"`
+	MimeType string `json:"mimeType"`
+}
+
+func NewAudioContent(data, mimeType string) *AudioContent {
+	return &AudioContent{
+		ContentBlock: ContentBlock{
+			Type: "audio",
+		},
+		Data:     data,
+		MimeType: mimeType,
+	}
+}
+
+// EmbeddedResource is the contents of a resource, embedded into a prompt or tool call result.
+type EmbeddedResource struct {
+	ContentBlock
+	// Either a TextResourceContents or a BlobResourceContents.
+	Resource any `json:"resource"`
+}
+
+func NewEmbeddedResource(resource any) *EmbeddedResource {
+	return &EmbeddedResource{
+		ContentBlock: ContentBlock{
+			Type: "resource",
+		},
+		Resource: resource,
+	}
+}
+
 type Role string
 
 var (
@@ -97,6 +149,24 @@ func (i *InputSchema) SetRequired(required ...string) *InputSchema {
 	return i
 }
 
+// Validate validates tool arguments against the schema and returns all violations. An empty
+// type is treated as "object", the only type tool arguments can have.
+func (i *InputSchema) Validate(arguments schema.M) []schema.Violation {
+	t := i.Type
+	if t == "" {
+		t = "object"
+	}
+	s := schema.M{
+		"type":       t,
+		"properties": i.Properties,
+		"required":   i.Required,
+	}
+	if arguments == nil {
+		arguments = schema.M{}
+	}
+	return schema.Validate(s, arguments)
+}
+
 type OutputSchema struct {
 	Properties schema.M `json:"properties,omitempty"`
 	Required   []string `json:"required,omitempty"`
@@ -136,6 +206,28 @@ func NewNumberProperty(description string) schema.M {
 	}
 }
 
+func NewIntegerProperty(description string) schema.M {
+	return schema.M{
+		"type":        "integer",
+		"description": description,
+	}
+}
+
+func NewBooleanProperty(description string) schema.M {
+	return schema.M{
+		"type":        "boolean",
+		"description": description,
+	}
+}
+
+func NewEnumProperty(description string, values ...string) schema.M {
+	return schema.M{
+		"type":        "string",
+		"description": description,
+		"enum":        values,
+	}
+}
+
 // Resource is a known resource that the server is capable of reading.
 type Resource struct {
 	// A description of what this resource represents.
@@ -0,0 +1,24 @@
+package protocol
+
+const (
+	ProtocolVersion20241105 = "2024-11-05"
+	ProtocolVersion20250326 = "2025-03-26"
+	ProtocolVersion20250618 = "2025-06-18"
+
+	// LatestProtocolVersion is the newest protocol version supported by this package.
+	LatestProtocolVersion = ProtocolVersion20250618
+)
+
+// SupportedProtocolVersions are the protocol versions supported by this package, newest first.
+var SupportedProtocolVersions = []string{
+	ProtocolVersion20250618,
+	ProtocolVersion20250326,
+	ProtocolVersion20241105,
+}
+
+// UnsupportedProtocolVersionData is the data of the error returned when the server does not
+// support the protocol version requested by the client.
+type UnsupportedProtocolVersionData struct {
+	Requested string   `json:"requested"`
+	Supported []string `json:"supported"`
+}
@@ -0,0 +1,25 @@
+package gomcp
+
+import "slices"
+
+// The registries of the server are copied on write, so that handlers can iterate over them
+// without holding the lock.
+
+// upsert returns a copy of items in which the first item matching same is replaced by item. If
+// no item matches, item is appended.
+func upsert[T any](items []T, item T, same func(T) bool) []T {
+	items = slices.Clone(items)
+	if i := slices.IndexFunc(items, same); i >= 0 {
+		items[i] = item
+		return items
+	}
+	return append(items, item)
+}
+
+// remove returns a copy of items without the items matching same, and whether any matched.
+func remove[T any](items []T, same func(T) bool) ([]T, bool) {
+	if !slices.ContainsFunc(items, same) {
+		return items, false
+	}
+	return slices.DeleteFunc(slices.Clone(items), same), true
+}
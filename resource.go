@@ -5,12 +5,15 @@ import (
 	"errors"
 
 	"github.com/cfichtmueller/gomcp/protocol"
+	"github.com/cfichtmueller/gomcp/uritemplate"
 )
 
 type Resource struct {
-	Name    string
-	Uri     string
-	Handler func(ctx context.Context) *protocol.ReadResourceResult
+	Name string
+	Uri  string
+	// Handler reads the resource. Errors are sent to the client as JSON-RPC errors; return an
+	// *Error to control the code and data.
+	Handler func(ctx context.Context) (*protocol.ReadResourceResult, error)
 }
 
 var ErrNoSuchResource = errors.New("no such resource")
@@ -20,8 +23,42 @@ type ResourceTemplate struct {
 	MimeType    string
 	Name        string
 	Title       string
+	// UriTemplate is an RFC 6570 URI template.
 	UriTemplate string
+	// Handler reads a resource whose URI matches UriTemplate. It receives the values of the
+	// template variables. If the resource does not exist, it returns ErrNoSuchResource.
+	Handler func(ctx context.Context, uri string, vars map[string]string) (*protocol.ReadResourceResult, error)
 	// Read attempts to read a resource using the given URI. If the URI cannot be resolved using
-	// this template, it returns ErrNoSuchResource.
+	// this template, it returns ErrNoSuchResource. It is used for URIs that do not match
+	// UriTemplate, or if Handler is not set.
 	Read func(ctx context.Context, uri string) (*protocol.ReadResourceResult, error)
+	// Complete maps the names of template variables to functions returning completion values
+	// for them.
+	Complete map[string]CompleteFunc
+	template *uritemplate.Template
+}
+
+// read reads a resource using the handler if uri matches the template, or else using Read.
+func (t *ResourceTemplate) read(ctx context.Context, uri string) (*protocol.ReadResourceResult, error) {
+	if t.Handler != nil {
+		if vars, ok := t.template.Match(uri); ok {
+			return t.Handler(ctx, uri, vars)
+		}
+	}
+	if t.Read != nil {
+		return t.Read(ctx, uri)
+	}
+	return nil, ErrNoSuchResource
+}
+
+// ResourceProvider exposes resources that are not registered up front, e.g. the rows of a table
+// or the files in a directory.
+type ResourceProvider interface {
+	// List returns a page of resources and the cursor of the next page. An empty cursor requests
+	// the first page, an empty next cursor marks the last page. The cursor is opaque to the
+	// server, so providers can paginate using their backend's own tokens.
+	List(ctx context.Context, cursor string) ([]*protocol.Resource, string, error)
+	// Read reads the resource with the given URI. If the provider does not know the URI, it
+	// returns ErrNoSuchResource.
+	Read(ctx context.Context, uri string) (*protocol.ReadResourceResult, error)
 }
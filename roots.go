@@ -0,0 +1,68 @@
+package gomcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// Root is a root directory or file of the client that the server can operate on.
+type Root struct {
+	Name string
+	// Uri is a file:// URI.
+	Uri *url.URL
+}
+
+// Path returns the path of the root in the local file system.
+func (r *Root) Path() string {
+	return filepath.FromSlash(r.Uri.Path)
+}
+
+// Roots returns the roots of the client whose request is handled in ctx. The roots are requested
+// with roots/list once and cached in the session until the client notifies the server that they
+// changed. Clients that do not send such notifications are asked on every call. It returns
+// ErrCapabilityNotSupported if the client did not declare the roots capability.
+func Roots(ctx context.Context) ([]*Root, error) {
+	session := sessionFromContext(ctx)
+	if session == nil || !session.supports(ClientCapabilityRoots) {
+		return nil, ErrCapabilityNotSupported
+	}
+
+	roots, ok := session.roots()
+	if !ok || !session.supports(ClientCapabilityRootsListChanged) {
+		result := &protocol.ListRootsResult{}
+		if err := request(ctx, "roots/list", struct{}{}, result); err != nil {
+			return nil, err
+		}
+		roots = result.Roots
+		if roots == nil {
+			roots = make([]*protocol.Root, 0)
+		}
+		if session.supports(ClientCapabilityRootsListChanged) {
+			session.setRoots(roots)
+		}
+	}
+
+	parsed := make([]*Root, 0, len(roots))
+	for _, root := range roots {
+		u, err := url.Parse(root.Uri)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root uri %q: %w", root.Uri, err)
+		}
+		if u.Scheme != "file" {
+			return nil, fmt.Errorf("invalid root uri %q: scheme must be file", root.Uri)
+		}
+		parsed = append(parsed, &Root{Name: root.Name, Uri: u})
+	}
+	return parsed, nil
+}
+
+func (s *Server) handleRootsListChangedNotification(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
+	if session := sessionFromContext(ctx); session != nil {
+		session.setRoots(nil)
+	}
+	return NotificationResponse()
+}
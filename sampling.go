@@ -0,0 +1,18 @@
+package gomcp
+
+import (
+	"context"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// CreateMessage asks the client whose request is handled in ctx to sample an LLM, and waits for
+// the result. It returns ErrCapabilityNotSupported if the client did not declare the sampling
+// capability.
+func CreateMessage(ctx context.Context, params *protocol.CreateMessageParams) (*protocol.CreateMessageResult, error) {
+	result := &protocol.CreateMessageResult{}
+	if err := request(ctx, "sampling/createMessage", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
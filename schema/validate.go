@@ -0,0 +1,292 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Violation describes a part of a value that does not match its schema.
+type Violation struct {
+	// Path locates the offending value, e.g. items[2].name. It is empty for the value itself.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// Validate validates a JSON value, as produced by encoding/json, against a JSON schema and
+// returns all violations. It supports the keywords type, enum, const, required, properties,
+// additionalProperties, items, minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+// minLength, maxLength, pattern, minItems and maxItems. Other keywords are ignored.
+func Validate(schema M, value any) []Violation {
+	v := &validator{}
+	v.validate("", schema, value)
+	return v.violations
+}
+
+type validator struct {
+	violations []Violation
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validate(path string, schema map[string]any, value any) {
+	if types := stringList(schema["type"]); len(types) > 0 {
+		if !slices.ContainsFunc(types, func(t string) bool { return hasType(value, t) }) {
+			// The keywords that apply to the actual type are still checked, so all problems of
+			// a value are reported at once.
+			v.fail(path, "must be of type %s, got %s", strings.Join(types, " or "), typeOf(value))
+		}
+	}
+	if enum, ok := list(schema["enum"]); ok {
+		if !slices.ContainsFunc(enum, func(e any) bool { return equal(e, value) }) {
+			v.fail(path, "must be one of %s", formatList(enum))
+		}
+	}
+	if c, ok := schema["const"]; ok && !equal(c, value) {
+		v.fail(path, "must be %s", format(c))
+	}
+
+	switch value := value.(type) {
+	case float64:
+		v.validateNumber(path, schema, value)
+	case string:
+		v.validateString(path, schema, value)
+	case []any:
+		v.validateArray(path, schema, value)
+	case map[string]any:
+		v.validateObject(path, schema, value)
+	case M:
+		v.validateObject(path, schema, value)
+	}
+}
+
+func (v *validator) validateNumber(path string, schema map[string]any, value float64) {
+	if limit, ok := number(schema["minimum"]); ok && value < limit {
+		v.fail(path, "must be at least %s", format(limit))
+	}
+	if limit, ok := number(schema["maximum"]); ok && value > limit {
+		v.fail(path, "must be at most %s", format(limit))
+	}
+	if limit, ok := number(schema["exclusiveMinimum"]); ok && value <= limit {
+		v.fail(path, "must be greater than %s", format(limit))
+	}
+	if limit, ok := number(schema["exclusiveMaximum"]); ok && value >= limit {
+		v.fail(path, "must be less than %s", format(limit))
+	}
+}
+
+func (v *validator) validateString(path string, schema map[string]any, value string) {
+	length := float64(utf8.RuneCountInString(value))
+	if limit, ok := number(schema["minLength"]); ok && length < limit {
+		v.fail(path, "must be at least %s characters long", format(limit))
+	}
+	if limit, ok := number(schema["maxLength"]); ok && length > limit {
+		v.fail(path, "must be at most %s characters long", format(limit))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		// An invalid pattern is a mistake of the schema author, not of the caller.
+		if re := compilePattern(pattern); re != nil && !re.MatchString(value) {
+			v.fail(path, "must match the pattern %s", pattern)
+		}
+	}
+}
+
+// patterns caches compiled patterns, since schemas are validated repeatedly. Invalid patterns
+// are cached as nil.
+var patterns sync.Map
+
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	patterns.Store(pattern, re)
+	return re
+}
+
+func (v *validator) validateArray(path string, schema map[string]any, value []any) {
+	length := float64(len(value))
+	if limit, ok := number(schema["minItems"]); ok && length < limit {
+		v.fail(path, "must have at least %s items", format(limit))
+	}
+	if limit, ok := number(schema["maxItems"]); ok && length > limit {
+		v.fail(path, "must have at most %s items", format(limit))
+	}
+	if items, ok := object(schema["items"]); ok {
+		for i, item := range value {
+			v.validate(path+"["+strconv.Itoa(i)+"]", items, item)
+		}
+	}
+}
+
+func (v *validator) validateObject(path string, schema map[string]any, value map[string]any) {
+	for _, name := range stringList(schema["required"]) {
+		if _, ok := value[name]; !ok {
+			v.fail(join(path, name), "is required")
+		}
+	}
+	properties, _ := object(schema["properties"])
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if property, ok := object(properties[name]); ok {
+			v.validate(join(path, name), property, value[name])
+			continue
+		}
+		if _, ok := properties[name]; ok {
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.fail(join(path, name), "is not allowed")
+			}
+		default:
+			if s, ok := object(additional); ok {
+				v.validate(join(path, name), s, value[name])
+			}
+		}
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func hasType(value any, t string) bool {
+	switch t {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n) && !math.IsInf(n, 0)
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := object(value)
+		return ok
+	}
+	return false
+}
+
+func typeOf(value any) string {
+	for _, t := range []string{"null", "boolean", "integer", "number", "string", "array", "object"} {
+		if hasType(value, t) {
+			return t
+		}
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// object returns value as a map, whether it was built with M or decoded from JSON.
+func object(value any) (map[string]any, bool) {
+	switch value := value.(type) {
+	case map[string]any:
+		return value, true
+	case M:
+		return value, true
+	}
+	return nil, false
+}
+
+// list returns the elements of any slice, e.g. []string from a builder or []any from JSON.
+func list(value any) ([]any, bool) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	elements := make([]any, rv.Len())
+	for i := range elements {
+		elements[i] = rv.Index(i).Interface()
+	}
+	return elements, true
+}
+
+// stringList returns a single string or the strings of a slice.
+func stringList(value any) []string {
+	if s, ok := value.(string); ok {
+		return []string{s}
+	}
+	elements, _ := list(value)
+	strs := make([]string, 0, len(elements))
+	for _, e := range elements {
+		if s, ok := e.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// number converts the numeric types a schema built in Go may contain to float64.
+func number(value any) (float64, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func equal(a, b any) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func format(value any) string {
+	if n, ok := number(value); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
+
+func formatList(values []any) string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = format(value)
+	}
+	return strings.Join(strs, ", ")
+}
@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		value  string
+		want   []string
+	}{
+		{"type", `{"type":"string"}`, `"a"`, nil},
+		{"type mismatch", `{"type":"string"}`, `1`, []string{"must be of type string, got integer"}},
+		{"type list", `{"type":["string","null"]}`, `null`, nil},
+		{"integer", `{"type":"integer"}`, `7.5`, []string{"must be of type integer, got number"}},
+		{
+			"type mismatch and keywords of the actual type",
+			`{"type":"object","properties":{"n":{"type":"integer","maximum":5}}}`,
+			`{"n":7.5}`,
+			[]string{"n: must be of type integer, got number", "n: must be at most 5"},
+		},
+		{
+			"required",
+			`{"type":"object","required":["a","b"]}`,
+			`{"a":1}`,
+			[]string{"b: is required"},
+		},
+		{"enum", `{"enum":["a","b"]}`, `"c"`, []string{`must be one of "a", "b"`}},
+		{"enum numbers", `{"enum":[1,2]}`, `2`, nil},
+		{"const", `{"const":"a"}`, `"b"`, []string{`must be "a"`}},
+		{"minimum", `{"minimum":1}`, `0`, []string{"must be at least 1"}},
+		{"maximum", `{"maximum":1}`, `1`, nil},
+		{"exclusiveMinimum", `{"exclusiveMinimum":1}`, `1`, []string{"must be greater than 1"}},
+		{"exclusiveMaximum", `{"exclusiveMaximum":1}`, `1`, []string{"must be less than 1"}},
+		{"minLength", `{"minLength":3}`, `"äö"`, []string{"must be at least 3 characters long"}},
+		{"maxLength", `{"maxLength":2}`, `"äö"`, nil},
+		{"pattern", `{"pattern":"^[a-z]+$"}`, `"abc"`, nil},
+		{"pattern mismatch", `{"pattern":"^[a-z]+$"}`, `"ABC"`, []string{"must match the pattern ^[a-z]+$"}},
+		{"invalid pattern", `{"pattern":"("}`, `"abc"`, nil},
+		{"minItems", `{"minItems":2}`, `[1]`, []string{"must have at least 2 items"}},
+		{"maxItems", `{"maxItems":1}`, `[1,2]`, []string{"must have at most 1 items"}},
+		{
+			"items",
+			`{"type":"array","items":{"type":"string"}}`,
+			`["a",1,"c",true]`,
+			[]string{"[1]: must be of type string, got integer", "[3]: must be of type string, got boolean"},
+		},
+		{
+			"nested objects",
+			`{"type":"object","properties":{"user":{"type":"object","required":["name"],"properties":{"tags":{"type":"array","items":{"minLength":1}}}}}}`,
+			`{"user":{"tags":["a",""]}}`,
+			[]string{"user.name: is required", "user.tags[1]: must be at least 1 characters long"},
+		},
+		{
+			"additionalProperties false",
+			`{"type":"object","properties":{"a":{}},"additionalProperties":false}`,
+			`{"a":1,"b":2}`,
+			[]string{"b: is not allowed"},
+		},
+		{
+			"additionalProperties schema",
+			`{"type":"object","additionalProperties":{"type":"number"}}`,
+			`{"a":1,"b":"x"}`,
+			[]string{"b: must be of type number, got string"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema M
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+			var value any
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, violation := range Validate(schema, value) {
+				got = append(got, violation.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateGoValues(t *testing.T) {
+	schema := M{
+		"type":     "object",
+		"required": []string{"n"},
+		"properties": M{
+			"n": M{"type": "integer", "minimum": 1, "enum": []int{1, 2}},
+		},
+	}
+	if violations := Validate(schema, map[string]any{"n": float64(2)}); len(violations) > 0 {
+		t.Fatalf("expected no violations, got %v", violations)
+	}
+	if violations := Validate(schema, map[string]any{"n": float64(3)}); len(violations) != 1 {
+		t.Fatalf("expected one violation, got %v", violations)
+	}
+}
@@ -0,0 +1,47 @@
+package gomcp
+
+import "context"
+
+// semaphore limits concurrency. A nil semaphore imposes no limit.
+type semaphore chan struct{}
+
+func newSemaphore(limit int) semaphore {
+	if limit <= 0 {
+		return nil
+	}
+	return make(semaphore, limit)
+}
+
+// acquire waits for the semaphore. It fails if ctx is done, even if the semaphore is free.
+func (s semaphore) acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
+
+type semaphoreKey struct{}
+
+// withSemaphore sets the semaphore that requests handled in ctx hold while they are handled.
+func withSemaphore(ctx context.Context, s semaphore) context.Context {
+	return context.WithValue(ctx, semaphoreKey{}, s)
+}
+
+func semaphoreFromContext(ctx context.Context) semaphore {
+	s, _ := ctx.Value(semaphoreKey{}).(semaphore)
+	return s
+}
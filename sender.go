@@ -0,0 +1,50 @@
+package gomcp
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotConnected is returned when a server-initiated message cannot be delivered because there
+// is no channel to the client.
+var ErrNotConnected = errors.New("not connected to the client")
+
+// sender delivers server-initiated messages to a client.
+type sender interface {
+	send(message any) error
+	// disconnected returns a channel that is closed once the client can no longer answer
+	// requests, or nil if that cannot be detected.
+	disconnected() <-chan struct{}
+	// mustAnswer reports whether requests must be answered even if the client cancelled them,
+	// e.g. because the client waits for a response to each HTTP request.
+	mustAnswer() bool
+}
+
+// broadcaster is implemented by transports to deliver server-initiated messages to all of their
+// connected sessions.
+type broadcaster interface {
+	// broadcast sends message to each connected session for which filter returns true. A nil
+	// filter selects all sessions.
+	broadcast(message any, filter func(session *Session) bool)
+}
+
+type senderKey struct{}
+
+func withSender(ctx context.Context, s sender) context.Context {
+	return context.WithValue(ctx, senderKey{}, s)
+}
+
+func senderFromContext(ctx context.Context) sender {
+	s, _ := ctx.Value(senderKey{}).(sender)
+	return s
+}
+
+// Notify sends a notification to the client whose request is handled in ctx. Over HTTP, the
+// notification is delivered on the response stream of the request.
+func Notify(ctx context.Context, method string, params any) error {
+	s := senderFromContext(ctx)
+	if s == nil {
+		return ErrNotConnected
+	}
+	return s.send(NewJsonRpcNotification(method, params))
+}
@@ -3,17 +3,38 @@ package gomcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/cfichtmueller/gomcp/protocol"
+	"github.com/cfichtmueller/gomcp/uritemplate"
 )
 
 type Server struct {
 	info              *protocol.ServerInfo
 	instructions      string
+	pageSize          int
+	mutex             sync.RWMutex
 	tools             []*Tool
+	prompts           []*Prompt
 	resources         []*Resource
 	resourceTemplates []*ResourceTemplate
+	resourceProviders []ResourceProvider
 	handlers          map[string]HandleFunc
+	// notificationHandlers handle notifications. Unlike requests, notifications are never
+	// answered, so the two are kept apart.
+	notificationHandlers map[string]HandleFunc
+	broadcasters         []broadcaster
+	nextRequestId        atomic.Int64
+	pendingMutex         sync.Mutex
+	pending              map[string]chan *JsonRpcRequest
+	inFlightMutex        sync.Mutex
+	inFlight             map[string]*inFlightRequest
 }
 
 func NewServer(name, title, version string) *Server {
@@ -29,28 +50,83 @@ func NewServer(name, title, version string) *Server {
 			Title:   title,
 			Version: version,
 		},
-		tools:             make([]*Tool, 0),
-		resources:         make([]*Resource, 0),
-		resourceTemplates: make([]*ResourceTemplate, 0),
-		handlers:          make(map[string]HandleFunc),
+		tools:                make([]*Tool, 0),
+		prompts:              make([]*Prompt, 0),
+		resources:            make([]*Resource, 0),
+		resourceTemplates:    make([]*ResourceTemplate, 0),
+		handlers:             make(map[string]HandleFunc),
+		notificationHandlers: make(map[string]HandleFunc),
+		pending:              make(map[string]chan *JsonRpcRequest),
+		inFlight:             make(map[string]*inFlightRequest),
 	}
 
+	s.handlers["completion/complete"] = s.handleComplete
 	s.handlers["initialize"] = s.handleInitialize
 	s.handlers["logging/setLevel"] = s.handleLoggingSetLevel
-	s.handlers["notifications/initialized"] = s.handleInitializedNotification
 	s.handlers["ping"] = s.handlePing
+	s.handlers["prompts/get"] = s.handleGetPrompt
+	s.handlers["prompts/list"] = s.handleListPrompts
 	s.handlers["resources/list"] = s.handleListResources
 	s.handlers["resources/read"] = s.handleReadResource
+	s.handlers["resources/subscribe"] = s.handleSubscribeResource
 	s.handlers["resources/templates/list"] = s.handleListResourcesTemplates
+	s.handlers["resources/unsubscribe"] = s.handleUnsubscribeResource
 	s.handlers["tools/call"] = s.handleCallTool
 	s.handlers["tools/list"] = s.handleListTools
+
+	s.notificationHandlers["notifications/cancelled"] = s.handleCancelledNotification
+	s.notificationHandlers["notifications/initialized"] = s.handleInitializedNotification
+	s.notificationHandlers["notifications/roots/list_changed"] = s.handleRootsListChangedNotification
 	return s
 }
 
 func (s *Server) SetInstructions(instructions string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.instructions = instructions
 }
 
+// SetPageSize sets the maximum number of items returned by a list request. Clients fetch further
+// pages with the returned cursor. Zero, the default, returns all items at once.
+func (s *Server) SetPageSize(pageSize int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.pageSize = pageSize
+}
+
+// AddPrompt registers a prompt. A prompt with the same name is replaced. Connected sessions are
+// notified that the list of prompts changed.
+func (s *Server) AddPrompt(prompt *Prompt) {
+	if prompt.Name == "" {
+		panic("name is not set")
+	}
+	if prompt.Handler == nil {
+		panic("handler is not set")
+	}
+	s.mutex.Lock()
+	s.prompts = upsert(s.prompts, prompt, func(p *Prompt) bool {
+		return p.Name == prompt.Name
+	})
+	s.mutex.Unlock()
+	s.notifyListChanged("notifications/prompts/list_changed")
+}
+
+// RemovePrompt removes the prompt with the given name and reports whether it existed.
+func (s *Server) RemovePrompt(name string) bool {
+	s.mutex.Lock()
+	var removed bool
+	s.prompts, removed = remove(s.prompts, func(p *Prompt) bool {
+		return p.Name == name
+	})
+	s.mutex.Unlock()
+	if removed {
+		s.notifyListChanged("notifications/prompts/list_changed")
+	}
+	return removed
+}
+
+// AddResource registers a resource. A resource with the same URI is replaced. Connected
+// sessions are notified that the list of resources changed.
 func (s *Server) AddResource(resource *Resource) {
 	if resource.Name == "" {
 		panic("name is not set")
@@ -58,9 +134,49 @@ func (s *Server) AddResource(resource *Resource) {
 	if resource.Uri == "" {
 		panic("uri is not set")
 	}
-	s.resources = append(s.resources, resource)
+	s.mutex.Lock()
+	s.resources = upsert(s.resources, resource, func(r *Resource) bool {
+		return r.Uri == resource.Uri
+	})
+	s.mutex.Unlock()
+	s.notifyListChanged("notifications/resources/list_changed")
 }
 
+// RemoveResource removes the resource with the given URI and reports whether it existed.
+func (s *Server) RemoveResource(uri string) bool {
+	s.mutex.Lock()
+	var removed bool
+	s.resources, removed = remove(s.resources, func(r *Resource) bool {
+		return r.Uri == uri
+	})
+	s.mutex.Unlock()
+	if removed {
+		s.notifyListChanged("notifications/resources/list_changed")
+	}
+	return removed
+}
+
+// AddResourceProvider registers a provider of dynamic resources. Its resources are listed after
+// the statically registered ones. Connected sessions are notified that the list of resources
+// changed.
+func (s *Server) AddResourceProvider(provider ResourceProvider) {
+	if provider == nil {
+		panic("provider is not set")
+	}
+	s.mutex.Lock()
+	s.resourceProviders = append(slices.Clone(s.resourceProviders), provider)
+	s.mutex.Unlock()
+	s.NotifyResourcesListChanged()
+}
+
+// NotifyResourcesListChanged notifies connected sessions that the list of resources changed.
+// Servers with resource providers call it when the resources of a provider change.
+func (s *Server) NotifyResourcesListChanged() {
+	s.notifyListChanged("notifications/resources/list_changed")
+}
+
+// AddResourceTemplate registers a resource template. A template with the same name is replaced.
+// Connected sessions are notified that the list of resources changed.
 func (s *Server) AddResourceTemplate(template *ResourceTemplate) {
 	if template.Name == "" {
 		panic("name is not set")
@@ -68,12 +184,44 @@ func (s *Server) AddResourceTemplate(template *ResourceTemplate) {
 	if template.UriTemplate == "" {
 		panic("uri template is not set")
 	}
-	if template.Read == nil {
-		panic("read is not set")
+	if template.Handler == nil && template.Read == nil {
+		panic("handler is not set")
+	}
+	parsed, err := uritemplate.Parse(template.UriTemplate)
+	if err != nil {
+		panic(err)
+	}
+	for name := range template.Complete {
+		if !slices.Contains(parsed.Variables(), name) {
+			panic("completion for unknown variable " + name)
+		}
+	}
+	template.template = parsed
+	s.mutex.Lock()
+	s.resourceTemplates = upsert(s.resourceTemplates, template, func(t *ResourceTemplate) bool {
+		return t.Name == template.Name
+	})
+	s.mutex.Unlock()
+	s.notifyListChanged("notifications/resources/list_changed")
+}
+
+// RemoveResourceTemplate removes the resource template with the given name and reports whether
+// it existed.
+func (s *Server) RemoveResourceTemplate(name string) bool {
+	s.mutex.Lock()
+	var removed bool
+	s.resourceTemplates, removed = remove(s.resourceTemplates, func(t *ResourceTemplate) bool {
+		return t.Name == name
+	})
+	s.mutex.Unlock()
+	if removed {
+		s.notifyListChanged("notifications/resources/list_changed")
 	}
-	s.resourceTemplates = append(s.resourceTemplates, template)
+	return removed
 }
 
+// AddTool registers a tool. A tool with the same name is replaced. Connected sessions are
+// notified that the list of tools changed.
 func (s *Server) AddTool(tool *Tool) {
 	if tool.Name == "" {
 		panic("name is not set")
@@ -81,22 +229,175 @@ func (s *Server) AddTool(tool *Tool) {
 	if tool.InputSchema == nil {
 		panic("input schema is not set")
 	}
-	s.tools = append(s.tools, tool)
+	if tool.Handler == nil {
+		panic("handler is not set")
+	}
+	// The semaphore is set on a copy, since the tool may be in use if it is added again.
+	t := *tool
+	t.semaphore = newSemaphore(t.MaxConcurrency)
+	s.mutex.Lock()
+	s.tools = upsert(s.tools, &t, func(other *Tool) bool {
+		return other.Name == t.Name
+	})
+	s.mutex.Unlock()
+	s.notifyListChanged("notifications/tools/list_changed")
+}
+
+// RemoveTool removes the tool with the given name and reports whether it existed.
+func (s *Server) RemoveTool(name string) bool {
+	s.mutex.Lock()
+	var removed bool
+	s.tools, removed = remove(s.tools, func(t *Tool) bool {
+		return t.Name == name
+	})
+	s.mutex.Unlock()
+	if removed {
+		s.notifyListChanged("notifications/tools/list_changed")
+	}
+	return removed
+}
+
+func (s *Server) notifyListChanged(method string) {
+	s.broadcast(NewJsonRpcNotification(method, nil), func(session *Session) bool {
+		return session.isInitialized()
+	})
+}
+
+// NotifyResourceUpdated sends notifications/resources/updated to all connected sessions that
+// subscribed to the resource with the given URI.
+func (s *Server) NotifyResourceUpdated(uri string) {
+	s.broadcast(NewJsonRpcNotification("notifications/resources/updated", &protocol.ResourceUpdatedNotificationParams{
+		Uri: uri,
+	}), func(session *Session) bool {
+		return session.isSubscribed(uri)
+	})
+}
+
+func (s *Server) addBroadcaster(b broadcaster) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.broadcasters = append(s.broadcasters, b)
+}
+
+func (s *Server) removeBroadcaster(b broadcaster) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.broadcasters = slices.DeleteFunc(slices.Clone(s.broadcasters), func(other broadcaster) bool {
+		return other == b
+	})
+}
+
+// broadcast sends a message to the sessions of all transports for which filter returns true.
+func (s *Server) broadcast(message any, filter func(session *Session) bool) {
+	s.mutex.RLock()
+	broadcasters := s.broadcasters
+	s.mutex.RUnlock()
+
+	for _, b := range broadcasters {
+		b.broadcast(message, filter)
+	}
 }
 
 func (s *Server) handle(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
+	res, run := s.begin(ctx, message)
+	if run != nil {
+		return run()
+	}
+	return res
+}
+
+// begin checks a message, handles notifications and responses, and registers requests as in
+// flight, so that the client can cancel them from then on, even while they wait for the
+// semaphore of the transport. It returns either a response or a function that handles the
+// request.
+func (s *Server) begin(ctx context.Context, message *JsonRpcRequest) (*HandlerResponse, func() *HandlerResponse) {
+	if message.IsNotification() {
+		// Notifications are handled right away, so that they take effect in the order they
+		// were received. They are never answered, not even with an error.
+		if handler, ok := s.notificationHandlers[message.Method]; ok && message.Jsonrpc == "2.0" {
+			s.dispatch(ctx, handler, message)
+		}
+		return NotificationResponse(), nil
+	}
+	if message.Jsonrpc != "2.0" || message.Id == nil || (message.IsRequest() && !validId(message.Id)) {
+		var id json.RawMessage
+		if validId(message.Id) {
+			id = message.Id
+		}
+		return BadRequestResponse(NewErrorJsonRpcResponse(id, &JsonRpcError{
+			Code:    ErrorCodeInvalidRequest,
+			Message: "Invalid request",
+		})), nil
+	}
+	if message.IsResponse() {
+		return s.handleResponse(ctx, message), nil
+	}
+
 	handler, ok := s.handlers[message.Method]
 	if !ok {
-		return BadRequestResponse(&JsonRpcResponse{
-			Jsonrpc: "2.0",
-			Error: &JsonRpcError{
-				Code:    -32000,
-				Message: "Unsupported method",
-			},
-		})
+		return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+			Code:    ErrorCodeMethodNotFound,
+			Message: "Method not found",
+		})), nil
+	}
+
+	if message.Method == "initialize" {
+		return nil, func() *HandlerResponse {
+			return s.run(ctx, handler, message)
+		}
 	}
 
-	return handler(ctx, message)
+	ctx, done := s.track(ctx, message.Id)
+	return nil, func() *HandlerResponse {
+		res := s.run(ctx, handler, message)
+		if done() && !mustAnswer(ctx) {
+			// The client is no longer interested in the response.
+			return NotificationResponse()
+		}
+		return res
+	}
+}
+
+// run handles a request while holding the semaphore of the transport.
+func (s *Server) run(ctx context.Context, handler HandleFunc, message *JsonRpcRequest) *HandlerResponse {
+	sem := semaphoreFromContext(ctx)
+	if err := sem.acquire(ctx); err != nil {
+		return RequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+			Code:    ErrorCodeInternalError,
+			Message: "Request cancelled",
+		}))
+	}
+	defer sem.release()
+	return s.dispatch(ctx, handler, message)
+}
+
+// mustAnswer reports whether the transport of the request handled in ctx answers requests the
+// client cancelled.
+func mustAnswer(ctx context.Context) bool {
+	sender := senderFromContext(ctx)
+	return sender != nil && sender.mustAnswer()
+}
+
+func (s *Server) dispatch(ctx context.Context, handler HandleFunc, message *JsonRpcRequest) (res *HandlerResponse) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Handler panicked", "method", message.Method, "panic", r, "stack", string(debug.Stack()))
+			if message.IsRequest() {
+				res = ErrorResponse(message.Id, NewError(ErrorCodeInternalError, "Internal error"))
+			} else {
+				res = NotificationResponse()
+			}
+		}
+	}()
+
+	var params struct {
+		Meta *protocol.RequestMeta `json:"_meta"`
+	}
+	if json.Unmarshal(message.Params, &params) == nil && params.Meta != nil {
+		ctx = withProgressToken(ctx, params.Meta.ProgressToken)
+	}
+
+	return handler(withServer(ctx, s), message)
 }
 
 func (s *Server) handleInitialize(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
@@ -104,23 +405,49 @@ func (s *Server) handleInitialize(ctx context.Context, message *JsonRpcRequest)
 	if r := s.mustParseParams(message, &params); r != nil {
 		return r
 	}
+	version, ok := negotiateProtocolVersion(params.ProtocolVersion)
+	if !ok {
+		return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+			Code:    ErrorCodeInvalidParams,
+			Message: "Unsupported protocol version",
+			Data: &protocol.UnsupportedProtocolVersionData{
+				Requested: params.ProtocolVersion,
+				Supported: protocol.SupportedProtocolVersions,
+			},
+		}))
+	}
 
+	// Tools, prompts and resources can be registered at any time, so the capabilities are
+	// advertised even if there are none yet. Clients learn about additions through the
+	// list_changed notifications.
 	caps := protocol.NewServerCapabilities()
-	if len(s.tools) > 0 {
-		caps.Tools = protocol.NewCapability().SetListChanged(true)
+	caps.Logging = protocol.NewCapability()
+	caps.Tools = protocol.NewCapability().SetListChanged(true)
+	caps.Resources = protocol.NewCapability().SetListChanged(true).SetSubscribed(true)
+	caps.Prompts = protocol.NewCapability().SetListChanged(true)
+	if version >= protocol.ProtocolVersion20250326 {
+		caps.Completions = protocol.NewCapability()
 	}
-	if len(s.resources) > 0 || len(s.resourceTemplates) > 0 {
-		caps.Resources = protocol.NewCapability().SetListChanged(true)
+	if session := sessionFromContext(ctx); session != nil {
+		session.initialize(version, params.ClientInfo, params.Capabilities)
 	}
+	s.mutex.RLock()
+	instructions := s.instructions
+	s.mutex.RUnlock()
 	return RequestResponse(NewResultJsonRpcResponse(message.Id, protocol.InitializeResult{
-		ProtocolVersion: params.ProtocolVersion,
+		ProtocolVersion: version,
 		Capabilities:    caps,
-		ServerInfo:      s.info,
-		Instructions:    s.instructions,
+		ServerInfo:      downgradeServerInfo(ctx, s.info),
+		Instructions:    instructions,
 	}))
 }
 
 func (s *Server) handleInitializedNotification(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
+	if session := sessionFromContext(ctx); session != nil {
+		session.update(func(s *Session) {
+			s.Initialized = true
+		})
+	}
 	return NotificationResponse()
 }
 
@@ -129,38 +456,176 @@ func (s *Server) handleLoggingSetLevel(ctx context.Context, message *JsonRpcRequ
 	if r := s.mustParseParams(message, &params); r != nil {
 		return r
 	}
-	return NotificationResponse()
+	if !params.Level.Valid() {
+		return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+			Code:    ErrorCodeInvalidParams,
+			Message: "Invalid log level",
+		}))
+	}
+	if session := sessionFromContext(ctx); session != nil {
+		session.update(func(s *Session) {
+			s.LogLevel = params.Level
+		})
+	}
+	return RequestResponse(NewResultJsonRpcResponse(message.Id, map[string]any{}))
 }
 
 func (s *Server) handlePing(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
 	return RequestResponse(NewResultJsonRpcResponse(message.Id, map[string]any{}))
 }
 
+func (s *Server) handleListPrompts(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
+	var params protocol.ListPromptsRequest
+	if r := s.mustParseParams(message, &params); r != nil {
+		return r
+	}
+	c, err := decodeCursor(params.Cursor)
+	if err != nil {
+		return s.invalidCursorResponse(message)
+	}
+	s.mutex.RLock()
+	prompts, nextCursor := page(s.prompts, c, s.pageSize)
+	s.mutex.RUnlock()
+
+	res := protocol.NewListPromptsResult().SetNextCursor(nextCursor)
+	for _, prompt := range prompts {
+		p := &protocol.Prompt{
+			Name:        prompt.Name,
+			Title:       prompt.Title,
+			Description: prompt.Description,
+		}
+		for _, argument := range prompt.Arguments {
+			p.Arguments = append(p.Arguments, &protocol.PromptArgument{
+				Name:        argument.Name,
+				Title:       argument.Title,
+				Description: argument.Description,
+				Required:    argument.Required,
+			})
+		}
+		res.AddPrompt(downgradePrompt(ctx, p))
+	}
+	return RequestResponse(NewResultJsonRpcResponse(message.Id, res))
+}
+
+func (s *Server) handleGetPrompt(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
+	var params protocol.GetPromptParams
+	if r := s.mustParseParams(message, &params); r != nil {
+		return r
+	}
+	var prompt *Prompt
+	s.mutex.RLock()
+	for _, p := range s.prompts {
+		if p.Name == params.Name {
+			prompt = p
+			break
+		}
+	}
+	s.mutex.RUnlock()
+	if prompt == nil {
+		return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+			Code:    ErrorCodeInvalidParams,
+			Message: "Prompt not found",
+		}))
+	}
+	for _, argument := range prompt.Arguments {
+		if _, ok := params.Arguments[argument.Name]; argument.Required && !ok {
+			return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+				Code:    ErrorCodeInvalidParams,
+				Message: "Missing required argument: " + argument.Name,
+			}))
+		}
+	}
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]string)
+	}
+	result, err := prompt.Handler(ctx, arguments)
+	if err != nil {
+		return ErrorResponse(message.Id, err)
+	}
+	if result == nil {
+		result = protocol.NewGetPromptResult()
+	}
+	return RequestResponse(NewResultJsonRpcResponse(message.Id, downgradeGetPromptResult(ctx, result)))
+}
+
 func (s *Server) handleListResources(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
 	var params protocol.ListResourcesRequest
 	if r := s.mustParseParams(message, &params); r != nil {
 		return r
 	}
+	c, err := decodeCursor(params.Cursor)
+	if err != nil {
+		return s.invalidCursorResponse(message)
+	}
+	s.mutex.RLock()
+	resources := s.resources
+	providers := s.resourceProviders
+	pageSize := s.pageSize
+	s.mutex.RUnlock()
+
+	if c.Provider > len(providers) {
+		return s.invalidCursorResponse(message)
+	}
+
 	res := protocol.NewListResourcesResult()
-	for _, resource := range s.resources {
-		res.AddResource(&protocol.Resource{
-			Name: resource.Name,
-			Uri:  resource.Uri,
-		})
+	if c.Provider == 0 {
+		items, nextCursor := page(resources, c, pageSize)
+		for _, resource := range items {
+			res.AddResource(&protocol.Resource{
+				Name: resource.Name,
+				Uri:  resource.Uri,
+			})
+		}
+		if nextCursor != "" || len(providers) == 0 {
+			return RequestResponse(NewResultJsonRpcResponse(message.Id, res.SetNextCursor(nextCursor)))
+		}
+		if len(items) > 0 {
+			next := cursor{Provider: 1}
+			return RequestResponse(NewResultJsonRpcResponse(message.Id, res.SetNextCursor(next.encode())))
+		}
+		c = cursor{Provider: 1}
+	}
+
+	items, providerCursor, err := providers[c.Provider-1].List(ctx, c.ProviderCursor)
+	if err != nil {
+		return ErrorResponse(message.Id, err)
+	}
+	for _, resource := range items {
+		res.AddResource(downgradeResource(ctx, resource))
+	}
+	if providerCursor != "" {
+		next := cursor{Provider: c.Provider, ProviderCursor: providerCursor}
+		res.SetNextCursor(next.encode())
+	} else if c.Provider < len(providers) {
+		next := cursor{Provider: c.Provider + 1}
+		res.SetNextCursor(next.encode())
 	}
 	return RequestResponse(NewResultJsonRpcResponse(message.Id, res))
 }
 
 func (s *Server) handleListResourcesTemplates(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
-	res := protocol.NewListResourcesTemplatesResult()
-	for _, template := range s.resourceTemplates {
-		res.AddResourceTemplate(&protocol.ResourceTemplate{
+	var params protocol.ListResourcesTemplatesRequest
+	if r := s.mustParseParams(message, &params); r != nil {
+		return r
+	}
+	c, err := decodeCursor(params.Cursor)
+	if err != nil {
+		return s.invalidCursorResponse(message)
+	}
+	s.mutex.RLock()
+	templates, nextCursor := page(s.resourceTemplates, c, s.pageSize)
+	s.mutex.RUnlock()
+
+	res := protocol.NewListResourcesTemplatesResult().SetNextCursor(nextCursor)
+	for _, template := range templates {
+		res.AddResourceTemplate(downgradeResourceTemplate(ctx, &protocol.ResourceTemplate{
 			Description: template.Description,
 			MimeType:    template.MimeType,
 			Title:       template.Title,
 			Name:        template.Name,
 			UriTemplate: template.UriTemplate,
-		})
+		}))
 	}
 	return RequestResponse(NewResultJsonRpcResponse(message.Id, res))
 }
@@ -170,73 +635,179 @@ func (s *Server) handleReadResource(ctx context.Context, message *JsonRpcRequest
 	if r := s.mustParseParams(message, &params); r != nil {
 		return r
 	}
-	for _, resource := range s.resources {
+	s.mutex.RLock()
+	resources := s.resources
+	providers := s.resourceProviders
+	templates := s.resourceTemplates
+	s.mutex.RUnlock()
+
+	for _, resource := range resources {
 		if resource.Uri == params.Uri {
-			r := resource.Handler(ctx)
-			return RequestResponse(NewResultJsonRpcResponse(message.Id, r))
+			r, err := resource.Handler(ctx)
+			if err != nil {
+				return s.readResourceErrorResponse(message, params.Uri, err)
+			}
+			return s.readResourceResponse(message, r)
 		}
 	}
-	for _, template := range s.resourceTemplates {
-		r, err := template.Read(ctx, params.Uri)
+	for _, provider := range providers {
+		r, err := provider.Read(ctx, params.Uri)
 		if err != nil {
 			if err == ErrNoSuchResource {
 				continue
 			}
-			panic(err) // TODO: handle error
+			return s.readResourceErrorResponse(message, params.Uri, err)
 		}
-		return RequestResponse(NewResultJsonRpcResponse(message.Id, r))
+		return s.readResourceResponse(message, r)
 	}
+	for _, template := range templates {
+		r, err := template.read(ctx, params.Uri)
+		if err != nil {
+			if err == ErrNoSuchResource {
+				continue
+			}
+			return s.readResourceErrorResponse(message, params.Uri, err)
+		}
+		return s.readResourceResponse(message, r)
+	}
+	return s.resourceNotFoundResponse(message, params.Uri)
+}
+
+// readResourceResponse answers a resources/read request. A handler that returns no result read
+// a resource without contents.
+func (s *Server) readResourceResponse(message *JsonRpcRequest, result *protocol.ReadResourceResult) *HandlerResponse {
+	if result == nil {
+		result = protocol.NewReadResourceResult()
+	}
+	return RequestResponse(NewResultJsonRpcResponse(message.Id, result))
+}
+
+func (s *Server) resourceNotFoundResponse(message *JsonRpcRequest, uri string) *HandlerResponse {
 	return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
-		Code:    -32000,
+		Code:    ErrorCodeResourceNotFound,
 		Message: "Resource not found",
+		Data:    map[string]string{"uri": uri},
 	}))
 }
 
+// readResourceErrorResponse answers a resources/read request whose handler failed. Handlers of
+// static resources have no other way to report a missing resource than ErrNoSuchResource.
+func (s *Server) readResourceErrorResponse(message *JsonRpcRequest, uri string, err error) *HandlerResponse {
+	if errors.Is(err, ErrNoSuchResource) {
+		return s.resourceNotFoundResponse(message, uri)
+	}
+	return ErrorResponse(message.Id, err)
+}
+
+func (s *Server) handleSubscribeResource(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
+	var params protocol.SubscribeParams
+	if r := s.mustParseParams(message, &params); r != nil {
+		return r
+	}
+	session := sessionFromContext(ctx)
+	if session == nil {
+		return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+			Code:    ErrorCodeInvalidRequest,
+			Message: "Subscriptions require a session",
+		}))
+	}
+	session.subscribe(params.Uri)
+	return RequestResponse(NewResultJsonRpcResponse(message.Id, map[string]any{}))
+}
+
+func (s *Server) handleUnsubscribeResource(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
+	var params protocol.UnsubscribeParams
+	if r := s.mustParseParams(message, &params); r != nil {
+		return r
+	}
+	if session := sessionFromContext(ctx); session != nil {
+		session.unsubscribe(params.Uri)
+	}
+	return RequestResponse(NewResultJsonRpcResponse(message.Id, map[string]any{}))
+}
+
 func (s *Server) handleCallTool(ctx context.Context, message *JsonRpcRequest) *HandlerResponse {
 	var params protocol.CallToolsParams
 	if r := s.mustParseParams(message, &params); r != nil {
 		return r
 	}
 	var tool *Tool
+	s.mutex.RLock()
 	for _, t := range s.tools {
 		if t.Name == params.Name {
 			tool = t
 			break
 		}
-
 	}
+	s.mutex.RUnlock()
 	if tool == nil {
 		return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
-			Code:    -32000,
+			Code:    ErrorCodeInvalidParams,
 			Message: "Tool not found",
 		}))
 	}
+	if violations := tool.InputSchema.Validate(params.Arguments); len(violations) > 0 {
+		messages := make([]string, len(violations))
+		for i, violation := range violations {
+			messages[i] = violation.String()
+		}
+		return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+			Code:    ErrorCodeInvalidParams,
+			Message: "Invalid arguments: " + strings.Join(messages, "; "),
+			Data:    map[string]any{"violations": violations},
+		}))
+	}
 	args := NewToolArguments(params.Arguments)
-	return RequestResponse(NewResultJsonRpcResponse(message.Id, tool.Call(ctx, args)))
-
+	result, err := tool.Call(ctx, args)
+	if err != nil {
+		return ErrorResponse(message.Id, err)
+	}
+	return RequestResponse(NewResultJsonRpcResponse(message.Id, downgradeCallToolsResult(ctx, result)))
 }
 
 func (s *Server) handleListTools(ctx context.Context, request *JsonRpcRequest) *HandlerResponse {
-	res := protocol.NewListToolsResult()
-	for _, tool := range s.tools {
-		res.AddTool(&protocol.Tool{
+	var params protocol.ListToolsRequest
+	if r := s.mustParseParams(request, &params); r != nil {
+		return r
+	}
+	c, err := decodeCursor(params.Cursor)
+	if err != nil {
+		return s.invalidCursorResponse(request)
+	}
+	s.mutex.RLock()
+	tools, nextCursor := page(s.tools, c, s.pageSize)
+	s.mutex.RUnlock()
+
+	res := protocol.NewListToolsResult().SetNextCursor(nextCursor)
+	for _, tool := range tools {
+		res.AddTool(downgradeTool(ctx, &protocol.Tool{
 			Name:         tool.Name,
 			Title:        tool.Title,
 			Description:  tool.Description,
 			InputSchema:  tool.InputSchema,
 			OutputSchema: tool.OutputSchema,
-		})
+		}))
 	}
 	return RequestResponse(NewResultJsonRpcResponse(request.Id, res))
 }
 
 func (s *Server) mustParseParams(message *JsonRpcRequest, params any) *HandlerResponse {
+	if len(message.Params) == 0 {
+		return nil
+	}
 	if err := json.Unmarshal(message.Params, &params); err != nil {
 		return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
-			Code:    -32000,
+			Code:    ErrorCodeInvalidParams,
 			Message: "Invalid params",
 		}))
 	}
 
 	return nil
 }
+
+func (s *Server) invalidCursorResponse(message *JsonRpcRequest) *HandlerResponse {
+	return BadRequestResponse(NewErrorJsonRpcResponse(message.Id, &JsonRpcError{
+		Code:    ErrorCodeInvalidParams,
+		Message: "Invalid cursor",
+	}))
+}
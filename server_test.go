@@ -0,0 +1,342 @@
+package gomcp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// call sends a request to server and returns the decoded response.
+func call(t *testing.T, server *Server, method string, params string) map[string]any {
+	t.Helper()
+	return callInSession(t, server, nil, method, params)
+}
+
+// callInSession sends a request of session to server and returns the decoded response.
+func callInSession(t *testing.T, server *Server, session *Session, method string, params string) map[string]any {
+	t.Helper()
+	ctx := context.Background()
+	if session != nil {
+		ctx = withSession(ctx, session)
+	}
+	res := server.handle(ctx, &JsonRpcRequest{
+		Jsonrpc: "2.0",
+		Method:  method,
+		Params:  json.RawMessage(params),
+		Id:      json.RawMessage(`1`),
+	})
+	if !res.SendBody {
+		t.Fatalf("%s: no response", method)
+	}
+	return decodeJson[map[string]any](t, res.payload())
+}
+
+// decodeJson converts value to T through JSON, as a client would see it.
+func decodeJson[T any](t *testing.T, value any) T {
+	t.Helper()
+	b, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded T
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestServerAnswersNilResultsWithEmptyResults(t *testing.T) {
+	server := newTestServer()
+	server.AddTool(&Tool{
+		Name:        "nothing",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			return nil, nil
+		},
+	})
+	server.AddPrompt(&Prompt{
+		Name: "nothing",
+		Handler: func(ctx context.Context, arguments map[string]string) (*protocol.GetPromptResult, error) {
+			return nil, nil
+		},
+	})
+	server.AddResource(&Resource{
+		Name: "nothing",
+		Uri:  "test://nothing",
+		Handler: func(ctx context.Context) (*protocol.ReadResourceResult, error) {
+			return nil, nil
+		},
+	})
+
+	tests := []struct {
+		method string
+		params string
+		field  string
+	}{
+		{"tools/call", `{"name":"nothing","arguments":{}}`, "content"},
+		{"prompts/get", `{"name":"nothing"}`, "messages"},
+		{"resources/read", `{"uri":"test://nothing"}`, "contents"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			response := call(t, server, tt.method, tt.params)
+			result, ok := response["result"].(map[string]any)
+			if !ok {
+				t.Fatalf("expected a result object, got %v", response)
+			}
+			if list, ok := result[tt.field].([]any); !ok || len(list) != 0 {
+				t.Errorf("expected empty %s, got %v", tt.field, result[tt.field])
+			}
+		})
+	}
+}
+
+func TestServerAdvertisesCapabilitiesWithoutRegistrations(t *testing.T) {
+	tests := []struct {
+		version     string
+		completions bool
+	}{
+		{protocol.ProtocolVersion20250618, true},
+		{protocol.ProtocolVersion20250326, true},
+		{protocol.ProtocolVersion20241105, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			response := call(t, newTestServer(), "initialize", `{"protocolVersion":"`+tt.version+`","capabilities":{},"clientInfo":{"name":"test","version":"1"}}`)
+			capabilities := response["result"].(map[string]any)["capabilities"].(map[string]any)
+			for _, name := range []string{"tools", "prompts", "resources"} {
+				capability, ok := capabilities[name].(map[string]any)
+				if !ok || capability["listChanged"] != true {
+					t.Errorf("expected %s with listChanged, got %v", name, capabilities[name])
+				}
+			}
+			if _, ok := capabilities["completions"]; ok != tt.completions {
+				t.Errorf("expected completions %v, got %v", tt.completions, capabilities["completions"])
+			}
+		})
+	}
+}
+
+func TestServerAnswersNotificationMethodsSentAsRequests(t *testing.T) {
+	server := newTestServer()
+	for _, method := range []string{"notifications/initialized", "notifications/cancelled", "notifications/roots/list_changed"} {
+		t.Run(method, func(t *testing.T) {
+			response := call(t, server, method, `{}`)
+			e, _ := response["error"].(map[string]any)
+			if e == nil || e["code"] != float64(ErrorCodeMethodNotFound) || response["id"] != float64(1) {
+				t.Fatalf("expected a method not found error, got %v", response)
+			}
+		})
+	}
+}
+
+func TestServerRemovesContentUnknownToOlderClients(t *testing.T) {
+	server := newTestServer()
+	server.AddTool(&Tool{
+		Name:        "content",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			return protocol.NewCallToolsResult().
+				AddContent(protocol.NewTextContent().SetText("text")).
+				AddContent(protocol.NewAudioContent("AAAA", "audio/wav")).
+				AddContent(protocol.NewResourceLink()).
+				AddContent(map[string]any{"type": "audio", "data": "AAAA", "mimeType": "audio/wav"}), nil
+		},
+	})
+	server.AddPrompt(&Prompt{
+		Name: "content",
+		Handler: func(ctx context.Context, arguments map[string]string) (*protocol.GetPromptResult, error) {
+			return protocol.NewGetPromptResult().
+				AddMessage(protocol.NewPromptMessage(protocol.RoleUser, protocol.NewTextContent().SetText("text"))).
+				AddMessage(protocol.NewPromptMessage(protocol.RoleUser, protocol.NewAudioContent("AAAA", "audio/wav"))).
+				AddMessage(protocol.NewPromptMessage(protocol.RoleUser, protocol.NewResourceLink())), nil
+		},
+	})
+
+	tests := []struct {
+		version string
+		content []string
+		prompt  []string
+	}{
+		{protocol.ProtocolVersion20250618, []string{"text", "audio", "resource_link", "audio"}, []string{"text", "audio", "resource_link"}},
+		{protocol.ProtocolVersion20250326, []string{"text", "audio", "audio"}, []string{"text", "audio"}},
+		{protocol.ProtocolVersion20241105, []string{"text"}, []string{"text"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			session := NewSession()
+			session.ProtocolVersion = tt.version
+
+			response := callInSession(t, server, session, "tools/call", `{"name":"content","arguments":{}}`)
+			var types []string
+			for _, content := range response["result"].(map[string]any)["content"].([]any) {
+				types = append(types, content.(map[string]any)["type"].(string))
+			}
+			if !slices.Equal(types, tt.content) {
+				t.Errorf("tools/call: expected %v, got %v", tt.content, types)
+			}
+
+			response = callInSession(t, server, session, "prompts/get", `{"name":"content"}`)
+			types = nil
+			for _, message := range response["result"].(map[string]any)["messages"].([]any) {
+				types = append(types, message.(map[string]any)["content"].(map[string]any)["type"].(string))
+			}
+			if !slices.Equal(types, tt.prompt) {
+				t.Errorf("prompts/get: expected %v, got %v", tt.prompt, types)
+			}
+		})
+	}
+}
+
+func TestServerValidatesArgumentsOfSchemasWithoutType(t *testing.T) {
+	server := newTestServer()
+	server.AddTool(&Tool{
+		Name:        "untyped",
+		InputSchema: &protocol.InputSchema{Required: []string{"name"}},
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			return nil, nil
+		},
+	})
+
+	response := call(t, server, "tools/call", `{"name":"untyped","arguments":{"name":"x"}}`)
+	if result, _ := response["result"].(map[string]any); result == nil || result["isError"] == true {
+		t.Fatalf("expected a successful call, got %v", response)
+	}
+	response = call(t, server, "tools/call", `{"name":"untyped","arguments":{}}`)
+	if e, _ := response["error"].(map[string]any); e == nil || e["code"] != float64(ErrorCodeInvalidParams) {
+		t.Fatalf("expected an invalid params error, got %v", response)
+	}
+}
+
+func TestServerReplacesToolsInUse(t *testing.T) {
+	server := newTestServer()
+	tool := &Tool{
+		Name:           "busy",
+		InputSchema:    protocol.NewInputSchema(),
+		MaxConcurrency: 1,
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			return nil, nil
+		},
+	}
+	server.AddTool(tool)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			call(t, server, "tools/call", `{"name":"busy","arguments":{}}`)
+			call(t, server, "tools/list", `{}`)
+			call(t, server, "initialize", `{"protocolVersion":"`+protocol.LatestProtocolVersion+`","capabilities":{},"clientInfo":{"name":"test","version":"1"}}`)
+		}
+	}()
+	for range 100 {
+		server.AddTool(tool)
+		server.SetPageSize(1)
+		server.SetInstructions("instructions")
+	}
+	<-done
+}
+
+func TestServerRejectsPromptsWithoutRequiredArguments(t *testing.T) {
+	server := newTestServer()
+	server.AddPrompt(&Prompt{
+		Name: "greet",
+		Arguments: []*PromptArgument{
+			{Name: "name", Required: true},
+			{Name: "greeting"},
+		},
+		Handler: func(ctx context.Context, arguments map[string]string) (*protocol.GetPromptResult, error) {
+			return protocol.NewGetPromptResult().
+				AddMessage(protocol.NewPromptMessage(protocol.RoleUser, protocol.NewTextContent().SetText("Hello "+arguments["name"]))), nil
+		},
+	})
+
+	response := call(t, server, "prompts/get", `{"name":"greet","arguments":{"greeting":"Hi"}}`)
+	e, _ := response["error"].(map[string]any)
+	if e == nil || e["code"] != float64(ErrorCodeInvalidParams) || e["message"] != "Missing required argument: name" {
+		t.Fatalf("expected an invalid params error, got %v", response)
+	}
+
+	response = call(t, server, "prompts/get", `{"name":"greet","arguments":{"name":"Ada"}}`)
+	if _, ok := response["result"].(map[string]any); !ok {
+		t.Fatalf("expected a result with the required argument, got %v", response)
+	}
+}
+
+func TestServerCompletesArguments(t *testing.T) {
+	languages := []string{"go", "javascript", "python", "typescript"}
+	completeLanguage := func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+		var values []string
+		for _, language := range languages {
+			if strings.HasPrefix(language, value) {
+				values = append(values, language)
+			}
+		}
+		return values, nil
+	}
+	server := newTestServer()
+	server.AddPrompt(&Prompt{
+		Name:      "review",
+		Arguments: []*PromptArgument{{Name: "language", Complete: completeLanguage}, {Name: "style"}},
+		Handler: func(ctx context.Context, arguments map[string]string) (*protocol.GetPromptResult, error) {
+			return nil, nil
+		},
+	})
+	server.AddResourceTemplate(&ResourceTemplate{
+		Name:        "projects",
+		UriTemplate: "repo://{owner}/{repo}",
+		Handler: func(ctx context.Context, uri string, vars map[string]string) (*protocol.ReadResourceResult, error) {
+			return nil, ErrNoSuchResource
+		},
+		Complete: map[string]CompleteFunc{
+			"repo": func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+				return []string{arguments["owner"] + "/" + value + "-server"}, nil
+			},
+		},
+	})
+	many := make([]string, protocol.MaxCompletionValues+1)
+	server.AddPrompt(&Prompt{
+		Name: "many",
+		Arguments: []*PromptArgument{{Name: "value", Complete: func(ctx context.Context, value string, arguments map[string]string) ([]string, error) {
+			return many, nil
+		}}},
+		Handler: func(ctx context.Context, arguments map[string]string) (*protocol.GetPromptResult, error) {
+			return nil, nil
+		},
+	})
+
+	tests := []struct {
+		name    string
+		params  string
+		values  []string
+		hasMore bool
+	}{
+		{"prompt argument", `{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"language","value":"py"}}`, []string{"python"}, false},
+		{"argument without completion", `{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"style","value":""}}`, []string{}, false},
+		{"unknown prompt", `{"ref":{"type":"ref/prompt","name":"unknown"},"argument":{"name":"language","value":""}}`, []string{}, false},
+		{"template variable with context", `{"ref":{"type":"ref/resource","uri":"repo://{owner}/{repo}"},"argument":{"name":"repo","value":"mcp"},"context":{"arguments":{"owner":"acme"}}}`, []string{"acme/mcp-server"}, false},
+		{"truncated values", `{"ref":{"type":"ref/prompt","name":"many"},"argument":{"name":"value","value":""}}`, many[:protocol.MaxCompletionValues], true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := call(t, server, "completion/complete", tt.params)
+			result, ok := response["result"].(map[string]any)
+			if !ok {
+				t.Fatalf("expected a result, got %v", response)
+			}
+			completion := decodeJson[protocol.Completion](t, result["completion"])
+			if !slices.Equal(completion.Values, tt.values) || completion.HasMore != tt.hasMore {
+				t.Fatalf("expected %v with hasMore %v, got %+v", tt.values, tt.hasMore, completion)
+			}
+		})
+	}
+
+	response := call(t, server, "completion/complete", `{"ref":{"type":"ref/unknown"},"argument":{"name":"x","value":""}}`)
+	if e, _ := response["error"].(map[string]any); e == nil || e["code"] != float64(ErrorCodeInvalidParams) {
+		t.Fatalf("expected an invalid params error for an unknown reference type, got %v", response)
+	}
+}
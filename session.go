@@ -0,0 +1,187 @@
+package gomcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"sync"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+// Session holds the state a client negotiated during initialization, and the state it changed
+// afterwards.
+//
+// The fields are exported for SessionStore implementations. A session in use is shared by the
+// concurrent requests of its client and guarded by an internal lock, so handlers only get
+// snapshots of it from SessionFromContext.
+type Session struct {
+	Id                 string                       `json:"id"`
+	ProtocolVersion    string                       `json:"protocolVersion"`
+	ClientInfo         *protocol.ClientInfo         `json:"clientInfo,omitempty"`
+	ClientCapabilities *protocol.ClientCapabilities `json:"clientCapabilities,omitempty"`
+	// Initialized is set once the client sent notifications/initialized.
+	Initialized bool `json:"initialized"`
+	// LogLevel is the minimum level of log messages the client wants to receive.
+	LogLevel protocol.LoggingLevel `json:"logLevel,omitempty"`
+	// Subscriptions are the URIs of the resources the client subscribed to.
+	Subscriptions []string `json:"subscriptions,omitempty"`
+	// Roots are the roots of the client, as of the last roots/list request. Nil if they have not
+	// been requested yet or changed since.
+	Roots []*protocol.Root `json:"roots"`
+
+	mutex sync.RWMutex
+	// changes are the changes made since the session was loaded, kept so that they can be
+	// applied to a newer state of the session.
+	changes []func(s *Session)
+}
+
+func NewSession() *Session {
+	return &Session{
+		Id: newSessionId(),
+	}
+}
+
+// initialize stores what the client negotiated during initialization.
+func (s *Session) initialize(version string, info *protocol.ClientInfo, capabilities *protocol.ClientCapabilities) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ProtocolVersion = version
+	s.ClientInfo = info
+	s.ClientCapabilities = capabilities
+}
+
+func (s *Session) protocolVersion() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.ProtocolVersion
+}
+
+// snapshot returns a copy of the session that is safe to read while the session is in use.
+func (s *Session) snapshot() *Session {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return &Session{
+		Id:                 s.Id,
+		ProtocolVersion:    s.ProtocolVersion,
+		ClientInfo:         s.ClientInfo,
+		ClientCapabilities: s.ClientCapabilities,
+		Initialized:        s.Initialized,
+		LogLevel:           s.LogLevel,
+		Subscriptions:      slices.Clone(s.Subscriptions),
+		Roots:              slices.Clone(s.Roots),
+	}
+}
+
+// update applies a change to the session and records it. The change must only depend on its
+// argument, since it may be applied again to a newer state of the session.
+func (s *Session) update(change func(s *Session)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	change(s)
+	s.changes = append(s.changes, change)
+}
+
+// takeChanges returns and forgets the changes recorded since the last call.
+func (s *Session) takeChanges() []func(s *Session) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	changes := s.changes
+	s.changes = nil
+	return changes
+}
+
+// apply applies changes to the session without recording them.
+func (s *Session) apply(changes []func(s *Session)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, change := range changes {
+		change(s)
+	}
+}
+
+func (s *Session) isInitialized() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.Initialized
+}
+
+func (s *Session) logLevel() protocol.LoggingLevel {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.LogLevel
+}
+
+func (s *Session) subscribe(uri string) {
+	s.update(func(s *Session) {
+		if !slices.Contains(s.Subscriptions, uri) {
+			s.Subscriptions = append(s.Subscriptions, uri)
+		}
+	})
+}
+
+func (s *Session) unsubscribe(uri string) {
+	s.update(func(s *Session) {
+		s.Subscriptions = slices.DeleteFunc(slices.Clone(s.Subscriptions), func(u string) bool {
+			return u == uri
+		})
+	})
+}
+
+func (s *Session) isSubscribed(uri string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return slices.Contains(s.Subscriptions, uri)
+}
+
+func (s *Session) roots() ([]*protocol.Root, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.Roots, s.Roots != nil
+}
+
+func (s *Session) setRoots(roots []*protocol.Root) {
+	s.update(func(s *Session) {
+		s.Roots = roots
+	})
+}
+
+type sessionKey struct{}
+
+func withSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// SessionFromContext returns a snapshot of the session of the request handled in ctx, or nil if
+// the request is not associated with a session. Changes to the snapshot are not stored, and it
+// does not reflect changes made by concurrent requests afterwards.
+func SessionFromContext(ctx context.Context) *Session {
+	if session := sessionFromContext(ctx); session != nil {
+		return session.snapshot()
+	}
+	return nil
+}
+
+// sessionFromContext returns the session of the request handled in ctx itself.
+func sessionFromContext(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionKey{}).(*Session)
+	return session
+}
+
+func newSessionId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// validSessionId reports whether id could have been generated by newSessionId.
+func validSessionId(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
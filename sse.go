@@ -0,0 +1,113 @@
+package gomcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+var errStreamClosed = errors.New("stream is closed")
+
+// sseStream writes JSON-RPC messages as Server-Sent Events.
+type sseStream struct {
+	w      http.ResponseWriter
+	rc     *http.ResponseController
+	mutex  sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+func newSseStream(w http.ResponseWriter) *sseStream {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	rc.Flush()
+	return &sseStream{
+		w:    w,
+		rc:   rc,
+		done: make(chan struct{}),
+	}
+}
+
+func (s *sseStream) send(message any) error {
+	b, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return errStreamClosed
+	}
+	if _, err := fmt.Fprintf(s.w, "data: %s\n\n", b); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// close prevents further writes and signals done. It must be called before the HTTP handler
+// returns.
+func (s *sseStream) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+}
+
+// postStream is the sender for messages emitted while handling a POST request. The response is
+// upgraded to an SSE stream when the first message is sent. If the client does not accept
+// SSE, messages are sent on the session's GET stream instead.
+type postStream struct {
+	transport  *HttpTransport
+	sessionId  string
+	w          http.ResponseWriter
+	acceptsSse bool
+	mutex      sync.Mutex
+	stream     *sseStream
+	done       bool
+}
+
+func (p *postStream) send(message any) error {
+	if !p.acceptsSse {
+		return p.transport.send(p.sessionId, message)
+	}
+
+	p.mutex.Lock()
+	if p.done {
+		p.mutex.Unlock()
+		return errStreamClosed
+	}
+	if p.stream == nil {
+		p.transport.addStandardHeaders(p.w)
+		p.stream = newSseStream(p.w)
+	}
+	stream := p.stream
+	p.mutex.Unlock()
+
+	return stream.send(message)
+}
+
+// disconnected returns nil, since the client answers requests with separate POST requests that
+// do not depend on this one.
+func (p *postStream) disconnected() <-chan struct{} {
+	return nil
+}
+
+// mustAnswer reports true, since the client waits for a response to its POST.
+func (p *postStream) mustAnswer() bool {
+	return true
+}
+
+// finish prevents further messages and returns the SSE stream if the response has been upgraded.
+func (p *postStream) finish() *sseStream {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.done = true
+	return p.stream
+}
@@ -0,0 +1,205 @@
+package gomcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// StdioTransport serves a Server over newline-delimited JSON-RPC messages, as used by MCP hosts
+// that launch servers as local subprocesses.
+//
+// Since the output stream carries the protocol, the transport never logs to it. Log records are
+// written to stderr unless a different logger is set.
+type StdioTransport struct {
+	server     *Server
+	session    *Session
+	in         io.Reader
+	out        io.Writer
+	logger     *slog.Logger
+	semaphore  semaphore
+	writeMutex sync.Mutex
+	// closed is closed once the input is exhausted, so that requests to the client fail
+	// instead of waiting for an answer that cannot arrive.
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewStdioTransport creates a transport reading from os.Stdin and writing to os.Stdout.
+func NewStdioTransport(server *Server) *StdioTransport {
+	t := &StdioTransport{
+		server:  server,
+		session: NewSession(),
+		in:      os.Stdin,
+		out:     os.Stdout,
+		logger:  slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		closed:  make(chan struct{}),
+	}
+	return t
+}
+
+// SetInput sets the reader the transport receives messages from.
+func (t *StdioTransport) SetInput(in io.Reader) *StdioTransport {
+	t.in = in
+	return t
+}
+
+// SetOutput sets the writer the transport sends messages to.
+func (t *StdioTransport) SetOutput(out io.Writer) *StdioTransport {
+	t.out = out
+	return t
+}
+
+// SetLogger sets the logger used for transport errors. It must not write to the output.
+func (t *StdioTransport) SetLogger(logger *slog.Logger) *StdioTransport {
+	t.logger = logger
+	return t
+}
+
+// SetMaxConcurrency limits the number of requests handled concurrently. Notifications are not
+// limited. Zero means no limit.
+func (t *StdioTransport) SetMaxConcurrency(limit int) *StdioTransport {
+	t.semaphore = newSemaphore(limit)
+	return t
+}
+
+// Run reads and handles messages until the input is exhausted or ctx is cancelled. Requests are
+// handled concurrently. On EOF, requests to the client fail with ErrNotConnected, and Run waits
+// for in-flight requests to be answered and returns nil. On cancellation or a read error,
+// in-flight requests see a cancelled context and Run returns the error once they are done.
+// Server notifications are sent to the client only while Run is running.
+func (t *StdioTransport) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	t.server.addBroadcaster(t)
+	defer t.server.removeBroadcaster(t)
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go t.read(ctx, lines, readErr)
+
+	var wg sync.WaitGroup
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		case err := <-readErr:
+			t.closeOnce.Do(func() { close(t.closed) })
+			if err != io.EOF {
+				cancel()
+			}
+			wg.Wait()
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case line := <-lines:
+			// Lines are begun in order, so that a request is registered before a cancellation
+			// on a later line is handled.
+			if run := t.beginLine(ctx, line); run != nil {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					run()
+				}()
+			}
+		}
+	}
+}
+
+func (t *StdioTransport) read(ctx context.Context, lines chan<- []byte, readErr chan<- error) {
+	reader := bufio.NewReader(t.in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err != nil {
+			readErr <- err
+			return
+		}
+	}
+}
+
+// beginLine parses a line and begins handling its messages. It returns a function completing
+// the handling, or nil if the line has been handled already.
+func (t *StdioTransport) beginLine(ctx context.Context, line []byte) func() {
+	message, batch, err := ReadJsonRpcMessage(bytes.NewReader(line))
+	if err != nil {
+		t.write(NewErrorJsonRpcResponse(nil, readError(err)))
+		return nil
+	}
+
+	ctx = withSemaphore(withSession(withSender(ctx, t), t.session), t.semaphore)
+
+	var res *HandlerResponse
+	var run func() *HandlerResponse
+	if batch != nil {
+		res, run = t.server.beginBatch(ctx, batch)
+	} else {
+		res, run = t.server.begin(ctx, message)
+	}
+	if run == nil {
+		if res.SendBody {
+			t.write(res.payload())
+		}
+		return nil
+	}
+	return func() {
+		if res := run(); res.SendBody {
+			t.write(res.payload())
+		}
+	}
+}
+
+// Notify sends a notification to the client.
+func (t *StdioTransport) Notify(method string, params any) error {
+	return t.send(NewJsonRpcNotification(method, params))
+}
+
+func (t *StdioTransport) broadcast(message any, filter func(session *Session) bool) {
+	if filter != nil && !filter(t.session) {
+		return
+	}
+	t.write(message)
+}
+
+func (t *StdioTransport) disconnected() <-chan struct{} {
+	return t.closed
+}
+
+// mustAnswer reports false, since requests the client cancelled can be left unanswered.
+func (t *StdioTransport) mustAnswer() bool {
+	return false
+}
+
+func (t *StdioTransport) send(message any) error {
+	b, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	t.writeMutex.Lock()
+	defer t.writeMutex.Unlock()
+	_, err = t.out.Write(b)
+	return err
+}
+
+func (t *StdioTransport) write(message any) {
+	if err := t.send(message); err != nil {
+		t.logger.Error("Failed to write JSON-RPC message", "error", err)
+	}
+}
@@ -0,0 +1,422 @@
+package gomcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+const testTimeout = 2 * time.Second
+
+// stdioClient drives a StdioTransport through pipes.
+type stdioClient struct {
+	t         *testing.T
+	transport *StdioTransport
+	in        *io.PipeWriter
+	messages  chan map[string]any
+	done      chan error
+	cancel    context.CancelFunc
+}
+
+func newStdioClient(t *testing.T, server *Server) *stdioClient {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &stdioClient{
+		t:        t,
+		in:       inW,
+		messages: make(chan map[string]any, 16),
+		done:     make(chan error, 1),
+		cancel:   cancel,
+	}
+	transport := NewStdioTransport(server).SetInput(inR).SetOutput(outW)
+	c.transport = transport
+	go func() {
+		c.done <- transport.Run(ctx)
+	}()
+	go func() {
+		scanner := bufio.NewScanner(outR)
+		for scanner.Scan() {
+			var message map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+				t.Errorf("invalid output %q: %v", scanner.Text(), err)
+				continue
+			}
+			c.messages <- message
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		inW.Close()
+	})
+	return c
+}
+
+func (c *stdioClient) send(message string) {
+	c.t.Helper()
+	if _, err := io.WriteString(c.in, message+"\n"); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+}
+
+func (c *stdioClient) receive() map[string]any {
+	c.t.Helper()
+	select {
+	case message := <-c.messages:
+		return message
+	case <-time.After(testTimeout):
+		c.t.Fatal("timed out waiting for a message")
+		return nil
+	}
+}
+
+// expectNothing fails if a message arrives within a short time.
+func (c *stdioClient) expectNothing() {
+	c.t.Helper()
+	select {
+	case message := <-c.messages:
+		c.t.Fatalf("unexpected message %v", message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func (c *stdioClient) initialize(protocolVersion, capabilities string) {
+	c.t.Helper()
+	c.send(`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"protocolVersion":"` + protocolVersion + `","capabilities":` + capabilities + `,"clientInfo":{"name":"test","version":"1"}}}`)
+	if message := c.receive(); message["error"] != nil {
+		c.t.Fatalf("initialize failed: %v", message["error"])
+	}
+	c.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+}
+
+func (c *stdioClient) wait() error {
+	c.t.Helper()
+	select {
+	case err := <-c.done:
+		return err
+	case <-time.After(testTimeout):
+		c.t.Fatal("Run did not return")
+		return nil
+	}
+}
+
+func newTestServer() *Server {
+	return NewServer("test", "Test", "1.0.0")
+}
+
+func TestStdioTransportFailsClientRequestsOnEOF(t *testing.T) {
+	server := newTestServer()
+	called := make(chan error, 1)
+	server.AddTool(&Tool{
+		Name:        "sample",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			_, err := CreateMessage(ctx, protocol.NewCreateMessageParams(10))
+			called <- err
+			return nil, err
+		},
+	})
+	c := newStdioClient(t, server)
+	c.initialize(protocol.LatestProtocolVersion, `{"sampling":{}}`)
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"sample","arguments":{}}}`)
+	if method := c.receive()["method"]; method != "sampling/createMessage" {
+		t.Fatalf("expected sampling request, got %v", method)
+	}
+
+	c.in.Close()
+
+	if err := c.wait(); err != nil {
+		t.Fatalf("Run returned %v", err)
+	}
+	if err := <-called; !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected, got %v", err)
+	}
+	result := c.receive()["result"].(map[string]any)
+	if result["isError"] != true {
+		t.Fatalf("expected error result, got %v", result)
+	}
+}
+
+func TestStdioTransportAnswersInFlightRequestsOnEOF(t *testing.T) {
+	server := newTestServer()
+	release := make(chan struct{})
+	server.AddTool(&Tool{
+		Name:        "slow",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			<-release
+			return protocol.NewCallToolsResult().AddContent(protocol.NewTextContent().SetText("done")), nil
+		},
+	})
+	c := newStdioClient(t, server)
+	c.initialize(protocol.LatestProtocolVersion, `{}`)
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow","arguments":{}}}`)
+	c.in.Close()
+
+	select {
+	case err := <-c.done:
+		t.Fatalf("Run returned %v before the request was answered", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	response := c.receive()
+	if response["id"] != float64(1) || response["result"] == nil {
+		t.Fatalf("expected the tool result, got %v", response)
+	}
+	if err := c.wait(); err != nil {
+		t.Fatalf("Run returned %v", err)
+	}
+}
+
+func TestStdioTransportStopsOnCancellation(t *testing.T) {
+	server, started, stopped := newBlockingServer()
+	c := newStdioClient(t, server)
+	c.initialize(protocol.LatestProtocolVersion, `{}`)
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"block","arguments":{}}}`)
+	await(t, started, "tool did not start")
+
+	c.cancel()
+
+	await(t, stopped, "tool was not cancelled")
+	if err := c.wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestStdioTransportStopsOnReadError(t *testing.T) {
+	server, started, stopped := newBlockingServer()
+	c := newStdioClient(t, server)
+	c.initialize(protocol.LatestProtocolVersion, `{}`)
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"block","arguments":{}}}`)
+	await(t, started, "tool did not start")
+
+	readErr := errors.New("broken pipe")
+	c.in.CloseWithError(readErr)
+
+	await(t, stopped, "tool was not cancelled")
+	if err := c.wait(); !errors.Is(err, readErr) {
+		t.Fatalf("expected the read error, got %v", err)
+	}
+}
+
+func TestStdioTransportAnswersParseErrors(t *testing.T) {
+	c := newStdioClient(t, newTestServer())
+	c.send(`{"jsonrpc":`)
+	response := c.receive()
+	e, _ := response["error"].(map[string]any)
+	if e == nil || e["code"] != float64(ErrorCodeParseError) || response["id"] != nil {
+		t.Fatalf("expected a parse error, got %v", response)
+	}
+
+	// The transport keeps reading after a malformed line.
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	if response := c.receive(); response["id"] != float64(1) {
+		t.Fatalf("expected the ping response, got %v", response)
+	}
+}
+
+func TestSessionFromContextReturnsSnapshots(t *testing.T) {
+	server := newTestServer()
+	sessions := make(chan *Session, 10)
+	server.AddTool(&Tool{
+		Name:        "session",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			session := SessionFromContext(ctx)
+			// Reading the snapshot does not race with concurrent requests of the session.
+			_ = session.LogLevel
+			session.LogLevel = protocol.LoggingLevelEmergency
+			sessions <- session
+			return nil, nil
+		},
+	})
+	c := newStdioClient(t, server)
+	c.initialize(protocol.ProtocolVersion20250326, `{"roots":{}}`)
+	for i := range 5 {
+		c.send(`{"jsonrpc":"2.0","id":` + strconv.Itoa(2*i+1) + `,"method":"tools/call","params":{"name":"session","arguments":{}}}`)
+		c.send(`{"jsonrpc":"2.0","id":` + strconv.Itoa(2*i+2) + `,"method":"logging/setLevel","params":{"level":"debug"}}`)
+	}
+	for range 10 {
+		c.receive()
+	}
+
+	session := <-sessions
+	if session.ProtocolVersion != protocol.ProtocolVersion20250326 || session.ClientInfo.Name != "test" || session.ClientCapabilities.Roots == nil {
+		t.Fatalf("expected the negotiated state, got %+v", session)
+	}
+	if level := c.transport.session.snapshot().LogLevel; level != protocol.LoggingLevelDebug {
+		t.Fatalf("expected changes to the snapshot not to be stored, got level %q", level)
+	}
+}
+
+func TestStdioTransportAnswersNonObjectsAsInvalidRequests(t *testing.T) {
+	c := newStdioClient(t, newTestServer())
+	for _, message := range []string{`1`, `"x"`, `null`} {
+		c.send(message)
+		response := c.receive()
+		e, _ := response["error"].(map[string]any)
+		if e == nil || e["code"] != float64(ErrorCodeInvalidRequest) || response["id"] != nil {
+			t.Fatalf("%s: expected an invalid request error, got %v", message, response)
+		}
+	}
+}
+
+func TestStdioTransportDetachesWhenRunReturns(t *testing.T) {
+	server := newTestServer()
+	c := newStdioClient(t, server)
+	c.initialize(protocol.LatestProtocolVersion, `{}`)
+	c.in.Close()
+	if err := c.wait(); err != nil {
+		t.Fatalf("Run returned %v", err)
+	}
+
+	server.NotifyResourcesListChanged()
+	c.expectNothing()
+	if len(server.broadcasters) != 0 {
+		t.Fatalf("expected the transport to be removed, got %d broadcasters", len(server.broadcasters))
+	}
+}
+
+func TestStdioTransportFiltersLogMessagesByLevel(t *testing.T) {
+	server := newTestServer()
+	server.AddTool(&Tool{
+		Name:        "log",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			logger := Logger(ctx)
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Warn("warning")
+			return nil, nil
+		},
+	})
+	c := newStdioClient(t, server)
+	c.initialize(protocol.LatestProtocolVersion, `{}`)
+
+	// logged returns the levels of the messages logged by a tool call, in order.
+	logged := func(id string) []string {
+		t.Helper()
+		c.send(`{"jsonrpc":"2.0","id":` + id + `,"method":"tools/call","params":{"name":"log","arguments":{}}}`)
+		var levels []string
+		for {
+			message := c.receive()
+			if message["method"] != "notifications/message" {
+				return levels
+			}
+			levels = append(levels, message["params"].(map[string]any)["level"].(string))
+		}
+	}
+
+	if levels := logged("1"); !slices.Equal(levels, []string{"info", "warning"}) {
+		t.Fatalf("expected info and above by default, got %v", levels)
+	}
+	c.send(`{"jsonrpc":"2.0","id":2,"method":"logging/setLevel","params":{"level":"warning"}}`)
+	if response := c.receive(); response["result"] == nil {
+		t.Fatalf("expected setLevel to succeed, got %v", response)
+	}
+	if levels := logged("3"); !slices.Equal(levels, []string{"warning"}) {
+		t.Fatalf("expected warning and above, got %v", levels)
+	}
+	c.send(`{"jsonrpc":"2.0","id":4,"method":"logging/setLevel","params":{"level":"debug"}}`)
+	c.receive()
+	if levels := logged("5"); !slices.Equal(levels, []string{"debug", "info", "warning"}) {
+		t.Fatalf("expected all levels, got %v", levels)
+	}
+}
+
+func TestStdioTransportNotifiesSubscribedResources(t *testing.T) {
+	server := newTestServer()
+	c := newStdioClient(t, server)
+	c.initialize(protocol.LatestProtocolVersion, `{}`)
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"file:///a"}}`)
+	if response := c.receive(); response["result"] == nil {
+		t.Fatalf("expected subscribe to succeed, got %v", response)
+	}
+
+	server.NotifyResourceUpdated("file:///b")
+	c.expectNothing()
+	server.NotifyResourceUpdated("file:///a")
+	message := c.receive()
+	if message["method"] != "notifications/resources/updated" || message["params"].(map[string]any)["uri"] != "file:///a" {
+		t.Fatalf("expected an update of file:///a, got %v", message)
+	}
+
+	c.send(`{"jsonrpc":"2.0","id":2,"method":"resources/unsubscribe","params":{"uri":"file:///a"}}`)
+	c.receive()
+	server.NotifyResourceUpdated("file:///a")
+	c.expectNothing()
+}
+
+func TestStdioTransportCachesRoots(t *testing.T) {
+	server := newTestServer()
+	server.AddTool(&Tool{
+		Name:        "roots",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			roots, err := Roots(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := protocol.NewCallToolsResult()
+			for _, root := range roots {
+				result.AddContent(protocol.NewTextContent().SetText(root.Path()))
+			}
+			return result, nil
+		},
+	})
+
+	tests := []struct {
+		name         string
+		capabilities string
+		// requested lists whether each of three calls requests the roots. The client notifies
+		// that its roots changed before the third call.
+		requested []bool
+	}{
+		{"with list changed notifications", `{"roots":{"listChanged":true}}`, []bool{true, false, true}},
+		{"without list changed notifications", `{"roots":{}}`, []bool{true, true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newStdioClient(t, server)
+			c.initialize(protocol.LatestProtocolVersion, tt.capabilities)
+			root := ""
+			for i, requested := range tt.requested {
+				if requested {
+					root = "/project/" + strconv.Itoa(i)
+				}
+				if i == 2 {
+					c.send(`{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}`)
+				}
+				c.send(`{"jsonrpc":"2.0","id":` + strconv.Itoa(i+1) + `,"method":"tools/call","params":{"name":"roots","arguments":{}}}`)
+				message := c.receive()
+				if message["method"] == "roots/list" {
+					if !requested {
+						t.Fatalf("call %d: expected cached roots", i)
+					}
+					id, _ := json.Marshal(message["id"])
+					c.send(`{"jsonrpc":"2.0","id":` + string(id) + `,"result":{"roots":[{"uri":"file://` + root + `"}]}}`)
+					message = c.receive()
+				} else if requested {
+					t.Fatalf("call %d: expected a roots/list request, got %v", i, message)
+				}
+				content := message["result"].(map[string]any)["content"].([]any)
+				if len(content) != 1 {
+					t.Fatalf("call %d: expected one root, got %v", i, content)
+				}
+				if text := content[0].(map[string]any)["text"]; text != root {
+					t.Fatalf("call %d: expected %s, got %v", i, root, text)
+				}
+			}
+		})
+	}
+}
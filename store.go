@@ -0,0 +1,124 @@
+package gomcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionStore persists sessions for the HTTP transport. Sharing a store between several
+// replicas of a server allows clients to be served by any of them.
+//
+// Sessions expire when they have not been updated or touched for the TTL of the store.
+// Implementations must be safe for concurrent use.
+type SessionStore interface {
+	// Create stores a new session.
+	Create(ctx context.Context, session *Session) error
+	// Get returns the session with the given id, or ErrSessionNotFound if it does not exist or
+	// has expired.
+	Get(ctx context.Context, id string) (*Session, error)
+	// Update stores the changed state of an existing session and resets its expiry. The HTTP
+	// transport applies a request's changes to the session returned by Get right before calling
+	// Update, and serializes this within a process. Across replicas sharing a store, concurrent
+	// updates of the same session are last-writer-wins unless the store guards against them.
+	Update(ctx context.Context, session *Session) error
+	// Touch resets the expiry of a session.
+	Touch(ctx context.Context, id string) error
+	// Delete removes a session. Deleting a session that does not exist is not an error.
+	Delete(ctx context.Context, id string) error
+}
+
+type memorySessionEntry struct {
+	session   *Session
+	expiresAt time.Time
+}
+
+// MemorySessionStore keeps sessions in memory. It is the default store of the HTTP transport
+// and is only suitable for a single replica.
+type MemorySessionStore struct {
+	ttl      time.Duration
+	mutex    sync.Mutex
+	sessions map[string]*memorySessionEntry
+}
+
+func NewMemorySessionStore(ttl time.Duration) *MemorySessionStore {
+	return &MemorySessionStore{
+		ttl:      ttl,
+		sessions: make(map[string]*memorySessionEntry),
+	}
+}
+
+func (s *MemorySessionStore) Create(ctx context.Context, session *Session) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.removeExpired()
+	s.sessions[session.Id] = &memorySessionEntry{
+		session:   session,
+		expiresAt: time.Now().Add(s.ttl),
+	}
+	return nil
+}
+
+func (s *MemorySessionStore) Get(ctx context.Context, id string) (*Session, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	entry, err := s.entry(id)
+	if err != nil {
+		return nil, err
+	}
+	return entry.session, nil
+}
+
+func (s *MemorySessionStore) Update(ctx context.Context, session *Session) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	entry, err := s.entry(session.Id)
+	if err != nil {
+		return err
+	}
+	entry.session = session
+	entry.expiresAt = time.Now().Add(s.ttl)
+	return nil
+}
+
+func (s *MemorySessionStore) Touch(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	entry, err := s.entry(id)
+	if err != nil {
+		return err
+	}
+	entry.expiresAt = time.Now().Add(s.ttl)
+	return nil
+}
+
+func (s *MemorySessionStore) Delete(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+func (s *MemorySessionStore) entry(id string) (*memorySessionEntry, error) {
+	entry, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.sessions, id)
+		return nil, ErrSessionNotFound
+	}
+	return entry, nil
+}
+
+func (s *MemorySessionStore) removeExpired() {
+	now := time.Now()
+	for id, entry := range s.sessions {
+		if now.After(entry.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
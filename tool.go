@@ -2,6 +2,7 @@ package gomcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/cfichtmueller/gomcp/protocol"
@@ -14,14 +15,41 @@ type Tool struct {
 	Description  string
 	InputSchema  *protocol.InputSchema
 	OutputSchema *protocol.OutputSchema
-	Handler      func(ctx context.Context, arguments *ToolArguments) *protocol.CallToolsResult
+	// Handler executes the tool. Errors are reported to the client as a result with isError set,
+	// so the model can see them and react, except for *Error, which is sent as a JSON-RPC error.
+	Handler func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error)
+	// MaxConcurrency limits the number of concurrent calls of the tool. Further calls wait for
+	// a running call to finish. Zero means no limit.
+	MaxConcurrency int
+	semaphore      semaphore
 }
 
-func (t *Tool) Call(ctx context.Context, arguments *ToolArguments) *protocol.CallToolsResult {
+// Call executes the tool. Errors of the handler, except for *Error, are converted into a result
+// with isError set. A handler that returns neither a result nor an error yields an empty result.
+func (t *Tool) Call(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
 	if t.Handler == nil {
-		panic("tool handler is not set")
+		return nil, errors.New("tool handler is not set")
 	}
-	return t.Handler(ctx, arguments)
+	if err := t.semaphore.acquire(ctx); err != nil {
+		return toolErrorResult(err), nil
+	}
+	defer t.semaphore.release()
+	result, err := t.Handler(ctx, arguments)
+	if err != nil {
+		var e *Error
+		if errors.As(err, &e) {
+			return nil, err
+		}
+		return toolErrorResult(err), nil
+	}
+	if result == nil {
+		result = protocol.NewCallToolsResult()
+	}
+	return result, nil
+}
+
+func toolErrorResult(err error) *protocol.CallToolsResult {
+	return protocol.NewCallToolsResult().AddContent(protocol.NewTextContent().SetText(err.Error())).SetIsError(true)
 }
 
 type ToolArguments struct {
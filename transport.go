@@ -1,71 +1,344 @@
 package gomcp
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"mime"
 	"net/http"
 	"strings"
 	"sync"
+	"time"
+)
+
+const (
+	sessionIdHeader       = "Mcp-Session-Id"
+	protocolVersionHeader = "Mcp-Protocol-Version"
+	defaultSessionTtl     = 24 * time.Hour
+	// defaultSessionTouchInterval is how often sessions with an open GET stream are touched,
+	// so that they don't expire while the client listens without sending requests.
+	defaultSessionTouchInterval = time.Minute
 )
 
 type HttpTransport struct {
 	server             *Server
 	corsAllowedOrigins string
-	handleMutex        sync.Mutex
+	semaphore          semaphore
+	sessionStore       SessionStore
+	sessionMutex       sync.Mutex
+	touchInterval      time.Duration
+	streamsMutex       sync.Mutex
+	streams            map[string]map[*sseStream]struct{}
 }
 
 func NewHttpTransport(server *Server) *HttpTransport {
-	return &HttpTransport{
+	t := &HttpTransport{
 		server:             server,
 		corsAllowedOrigins: "*",
+		sessionStore:       NewMemorySessionStore(defaultSessionTtl),
+		touchInterval:      defaultSessionTouchInterval,
+		streams:            make(map[string]map[*sseStream]struct{}),
 	}
+	server.addBroadcaster(t)
+	return t
 }
 
-func (t *HttpTransport) Handle(w http.ResponseWriter, r *http.Request) {
-	t.handleMutex.Lock()
-	defer t.handleMutex.Unlock()
+// SetMaxConcurrency limits the number of requests handled concurrently. Further requests wait
+// until a running request is finished. Notifications are not limited. Zero means no limit. It
+// must be called before the transport starts serving requests.
+func (t *HttpTransport) SetMaxConcurrency(limit int) *HttpTransport {
+	t.semaphore = newSemaphore(limit)
+	return t
+}
+
+// SetSessionStore replaces the default in-memory session store. Sessions with an open GET
+// stream are touched every minute, so the TTL of the store must be longer than that.
+func (t *HttpTransport) SetSessionStore(store SessionStore) *HttpTransport {
+	t.sessionStore = store
+	return t
+}
+
+// Close detaches the transport from the server and closes all open GET streams. Server
+// notifications are no longer sent to the sessions of the transport.
+func (t *HttpTransport) Close() {
+	t.server.removeBroadcaster(t)
 
-	if r.Method == http.MethodOptions {
+	t.streamsMutex.Lock()
+	streams := t.streams
+	t.streams = make(map[string]map[*sseStream]struct{})
+	t.streamsMutex.Unlock()
+
+	for _, sessionStreams := range streams {
+		for stream := range sessionStreams {
+			stream.close()
+		}
+	}
+}
+
+func (t *HttpTransport) Handle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodOptions:
 		t.addStandardHeaders(w)
 		w.WriteHeader(http.StatusOK)
-		return
+	case http.MethodGet:
+		t.handleGet(w, r)
+	case http.MethodPost:
+		t.handlePost(w, r)
+	case http.MethodDelete:
+		t.handleDelete(w, r)
+	default:
+		t.addStandardHeaders(w)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Notify sends a notification to all initialized sessions that have a GET stream open. It
+// returns ErrNotConnected if the notification was not delivered to any session.
+func (t *HttpTransport) Notify(method string, params any) error {
+	delivered := t.deliver(NewJsonRpcNotification(method, params), func(session *Session) bool {
+		return session.isInitialized()
+	})
+	if delivered == 0 {
+		return ErrNotConnected
+	}
+	return nil
+}
+
+func (t *HttpTransport) broadcast(message any, filter func(session *Session) bool) {
+	t.deliver(message, filter)
+}
+
+// deliver sends a message to the sessions with an open GET stream for which filter returns true,
+// and returns the number of sessions that received it.
+func (t *HttpTransport) deliver(message any, filter func(session *Session) bool) int {
+	t.streamsMutex.Lock()
+	sessionIds := make([]string, 0, len(t.streams))
+	for sessionId := range t.streams {
+		sessionIds = append(sessionIds, sessionId)
+	}
+	t.streamsMutex.Unlock()
+
+	ctx := context.Background()
+	delivered := 0
+	for _, sessionId := range sessionIds {
+		if filter != nil {
+			session, err := t.sessionStore.Get(ctx, sessionId)
+			if err != nil || !filter(session) {
+				continue
+			}
+		}
+		if err := t.send(sessionId, message); err != nil {
+			slog.Error("Failed to send message", "session", sessionId, "error", err)
+			continue
+		}
+		delivered++
 	}
+	return delivered
+}
+
+// send delivers a message on one of the GET streams of a session.
+func (t *HttpTransport) send(sessionId string, message any) error {
+	t.streamsMutex.Lock()
+	streams := make([]*sseStream, 0, len(t.streams[sessionId]))
+	for stream := range t.streams[sessionId] {
+		streams = append(streams, stream)
+	}
+	t.streamsMutex.Unlock()
+
+	for _, stream := range streams {
+		if err := stream.send(message); err == nil {
+			return nil
+		}
+	}
+	return ErrNotConnected
+}
+
+// session looks up the session referenced by the request and checks that the request uses the
+// protocol version negotiated for it. Requests without a protocol version header are accepted
+// for compatibility with clients of older protocol versions. If the session cannot be found, or
+// the protocol version is unknown or does not match, an error is written and nil is returned.
+func (t *HttpTransport) session(w http.ResponseWriter, r *http.Request) *Session {
+	sessionId := r.Header.Get(sessionIdHeader)
+	if sessionId == "" {
+		t.addStandardHeaders(w)
+		http.Error(w, "Missing session id", http.StatusBadRequest)
+		return nil
+	}
+
+	version := r.Header.Get(protocolVersionHeader)
+	if version != "" && !supportedProtocolVersion(version) {
+		t.addStandardHeaders(w)
+		http.Error(w, "Unsupported protocol version", http.StatusBadRequest)
+		return nil
+	}
+
+	session, err := t.sessionStore.Get(r.Context(), sessionId)
+	if errors.Is(err, ErrSessionNotFound) {
+		t.addStandardHeaders(w)
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return nil
+	}
+	if err != nil {
+		slog.Error("Failed to get session", "session", sessionId, "error", err)
+		t.addStandardHeaders(w)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return nil
+	}
+	if version != "" && version != session.protocolVersion() {
+		t.addStandardHeaders(w)
+		http.Error(w, "Protocol version does not match the session", http.StatusBadRequest)
+		return nil
+	}
+	w.Header().Set(protocolVersionHeader, session.protocolVersion())
+	return session
+}
 
-	if r.Method != http.MethodPost {
+func (t *HttpTransport) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !accepts(r, "text/event-stream") {
 		t.addStandardHeaders(w)
-		http.Error(w, "Only POST method is supported", http.StatusMethodNotAllowed)
+		http.Error(w, "Client must accept text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	session := t.session(w, r)
+	if session == nil {
 		return
 	}
+	if err := t.sessionStore.Touch(r.Context(), session.Id); err != nil {
+		slog.Error("Failed to touch session", "session", session.Id, "error", err)
+	}
 
-	// Check if client accepts JSON
-	acceptsJson := false
-	for _, val := range r.Header.Values("Accept") {
-		for _, accepted := range strings.Split(val, ",") {
-			if accepted == "application/json" || accepted == "*/*" {
-				acceptsJson = true
-				break
+	t.addStandardHeaders(w)
+	stream := newSseStream(w)
+
+	t.streamsMutex.Lock()
+	if t.streams[session.Id] == nil {
+		t.streams[session.Id] = make(map[*sseStream]struct{})
+	}
+	t.streams[session.Id][stream] = struct{}{}
+	t.streamsMutex.Unlock()
+
+	ticker := time.NewTicker(t.touchInterval)
+	defer ticker.Stop()
+loop:
+	for {
+		select {
+		case <-r.Context().Done():
+			break loop
+		case <-stream.done:
+			break loop
+		case <-ticker.C:
+			err := t.sessionStore.Touch(r.Context(), session.Id)
+			if errors.Is(err, ErrSessionNotFound) {
+				break loop
+			}
+			if err != nil {
+				slog.Error("Failed to touch session", "session", session.Id, "error", err)
 			}
 		}
 	}
-	if !acceptsJson {
+
+	t.streamsMutex.Lock()
+	delete(t.streams[session.Id], stream)
+	if len(t.streams[session.Id]) == 0 {
+		delete(t.streams, session.Id)
+	}
+	t.streamsMutex.Unlock()
+	stream.close()
+}
+
+func (t *HttpTransport) handleDelete(w http.ResponseWriter, r *http.Request) {
+	session := t.session(w, r)
+	if session == nil {
+		return
+	}
+
+	if err := t.sessionStore.Delete(r.Context(), session.Id); err != nil {
+		slog.Error("Failed to delete session", "session", session.Id, "error", err)
+		t.addStandardHeaders(w)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	t.streamsMutex.Lock()
+	streams := t.streams[session.Id]
+	delete(t.streams, session.Id)
+	t.streamsMutex.Unlock()
+
+	for stream := range streams {
+		stream.close()
+	}
+
+	t.addStandardHeaders(w)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (t *HttpTransport) handlePost(w http.ResponseWriter, r *http.Request) {
+	if !accepts(r, "application/json") {
 		w.WriteHeader(http.StatusNotAcceptable)
 		return
 	}
 
-	message, err := ReadJsonRpcRequest(r.Body)
+	message, batch, err := ReadJsonRpcMessage(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		t.addStandardHeaders(w)
+		w.WriteHeader(http.StatusBadRequest)
+		NewErrorJsonRpcResponse(nil, readError(err)).Write(w)
 		return
 	}
 
-	ctx := r.Context()
+	initialize := message != nil && message.Method == "initialize"
+	var session *Session
+	if initialize {
+		session = NewSession()
+	} else if session = t.session(w, r); session == nil {
+		return
+	}
 
-	res := t.server.handle(ctx, message)
+	ps := &postStream{
+		transport:  t,
+		sessionId:  session.Id,
+		w:          w,
+		acceptsSse: accepts(r, "text/event-stream"),
+	}
+	ctx := withSemaphore(withSession(withSender(r.Context(), ps), session), t.semaphore)
+
+	var res *HandlerResponse
+	if batch != nil {
+		res = t.server.handleBatch(ctx, batch)
+	} else {
+		res = t.server.handle(ctx, message)
+	}
+
+	if initialize {
+		if res.Body != nil && res.Body.Error == nil {
+			if err := t.sessionStore.Create(r.Context(), session); err != nil {
+				slog.Error("Failed to create session", "error", err)
+				t.addStandardHeaders(w)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set(sessionIdHeader, session.Id)
+			w.Header().Set(protocolVersionHeader, session.protocolVersion())
+		}
+	} else {
+		t.saveSession(r.Context(), session)
+	}
+
+	if stream := ps.finish(); stream != nil {
+		if res.SendBody {
+			if err := stream.send(res.payload()); err != nil {
+				slog.Error("Failed to send JSON-RPC response", "error", err)
+			}
+		}
+		stream.close()
+		return
+	}
 
 	var body []byte
 
 	if res.SendBody {
-		bb, err := json.Marshal(res.Body)
+		bb, err := json.Marshal(res.payload())
 		if err != nil {
 			slog.Error("Failed to marshal JSON-RPC response", "error", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -81,11 +354,54 @@ func (t *HttpTransport) Handle(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// saveSession stores the changes a request made to session, or resets its expiry if there are
+// none. The changes are applied to the latest stored state of the session, so that concurrent
+// requests of the same session don't undo each other's changes.
+func (t *HttpTransport) saveSession(ctx context.Context, session *Session) {
+	changes := session.takeChanges()
+	if len(changes) == 0 {
+		if err := t.sessionStore.Touch(ctx, session.Id); err != nil {
+			slog.Error("Failed to touch session", "session", session.Id, "error", err)
+		}
+		return
+	}
+
+	t.sessionMutex.Lock()
+	defer t.sessionMutex.Unlock()
+	latest, err := t.sessionStore.Get(ctx, session.Id)
+	if err != nil {
+		slog.Error("Failed to update session", "session", session.Id, "error", err)
+		return
+	}
+	// Stores that keep sessions in memory return the session the changes were made to.
+	if latest != session {
+		latest.apply(changes)
+	}
+	if err := t.sessionStore.Update(ctx, latest); err != nil {
+		slog.Error("Failed to update session", "session", session.Id, "error", err)
+	}
+}
+
 func (t *HttpTransport) addStandardHeaders(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Mcp-Protocol-Version", "2025-06-18")
 	w.Header().Set("Access-Control-Allow-Origin", t.corsAllowedOrigins)
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "*")
-	w.Header().Set("Access-Control-Expose-Headers", "Mcp-Protocol-Version")
+	w.Header().Set("Access-Control-Expose-Headers", "Mcp-Protocol-Version, Mcp-Session-Id")
+}
+
+// accepts reports whether the Accept header of r includes mediaType or a wildcard.
+func accepts(r *http.Request, mediaType string) bool {
+	for _, val := range r.Header.Values("Accept") {
+		for _, accepted := range strings.Split(val, ",") {
+			accepted, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+			if err != nil {
+				continue
+			}
+			if accepted == mediaType || accepted == "*/*" {
+				return true
+			}
+		}
+	}
+	return false
 }
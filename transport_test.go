@@ -0,0 +1,465 @@
+package gomcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+)
+
+func TestHttpTransportMergesConcurrentSessionChanges(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileSessionStore(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := NewHttpTransport(newTestServer()).SetSessionStore(store)
+
+	session := NewSession()
+	session.Subscriptions = []string{"file:///a"}
+	if err := store.Create(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two requests of the same session load it before either of them is done.
+	first, _ := store.Get(ctx, session.Id)
+	second, _ := store.Get(ctx, session.Id)
+	first.subscribe("file:///b")
+	first.unsubscribe("file:///a")
+	second.setRoots([]*protocol.Root{{Uri: "file:///root"}})
+	second.update(func(s *Session) { s.LogLevel = protocol.LoggingLevelDebug })
+	transport.saveSession(ctx, first)
+	transport.saveSession(ctx, second)
+
+	stored, err := store.Get(ctx, session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.Subscriptions) != 1 || stored.Subscriptions[0] != "file:///b" {
+		t.Errorf("expected subscriptions [file:///b], got %v", stored.Subscriptions)
+	}
+	if len(stored.Roots) != 1 || stored.Roots[0].Uri != "file:///root" {
+		t.Errorf("expected roots to be stored, got %v", stored.Roots)
+	}
+	if stored.LogLevel != protocol.LoggingLevelDebug {
+		t.Errorf("expected log level debug, got %q", stored.LogLevel)
+	}
+}
+
+func TestHttpTransportChecksProtocolVersionHeader(t *testing.T) {
+	transport := NewHttpTransport(newTestServer())
+	session := NewSession()
+	session.ProtocolVersion = protocol.ProtocolVersion20250326
+	if err := transport.sessionStore.Create(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		version string
+		status  int
+		body    string
+	}{
+		{"", http.StatusOK, ""},
+		{protocol.ProtocolVersion20250326, http.StatusOK, ""},
+		{protocol.ProtocolVersion20250618, http.StatusBadRequest, "Protocol version does not match the session"},
+		{"2099-01-01", http.StatusBadRequest, "Unsupported protocol version"},
+		{"latest", http.StatusBadRequest, "Unsupported protocol version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+			r.Header.Set("Accept", "application/json, text/event-stream")
+			r.Header.Set(sessionIdHeader, session.Id)
+			if tt.version != "" {
+				r.Header.Set(protocolVersionHeader, tt.version)
+			}
+			w := httptest.NewRecorder()
+			transport.Handle(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+			if tt.body != "" && strings.TrimSpace(w.Body.String()) != tt.body {
+				t.Fatalf("expected %q, got %q", tt.body, w.Body)
+			}
+		})
+	}
+}
+
+func TestFileSessionStoreRemovesExpiredSessions(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewFileSessionStore(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	abandoned := NewSession()
+	if err := store.Create(ctx, abandoned); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, abandoned.Id+".json"), past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Create(ctx, NewSession()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, abandoned.Id+".json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected the expired session to be removed, got %v", err)
+	}
+}
+
+func TestHttpTransportTouchesSessionsWithOpenStreams(t *testing.T) {
+	store := NewMemorySessionStore(100 * time.Millisecond)
+	transport := NewHttpTransport(newTestServer()).SetSessionStore(store)
+	transport.touchInterval = 10 * time.Millisecond
+	session := NewSession()
+	if err := store.Create(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := httptest.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/event-stream")
+	r.Header.Set(sessionIdHeader, session.Id)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		transport.Handle(httptest.NewRecorder(), r)
+	}()
+
+	time.Sleep(300 * time.Millisecond)
+	if _, err := store.Get(context.Background(), session.Id); err != nil {
+		t.Fatalf("expected the session to be alive, got %v", err)
+	}
+	cancel()
+	<-done
+}
+
+func TestHttpTransportSeparatesParseAndShapeErrors(t *testing.T) {
+	transport := NewHttpTransport(newTestServer())
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"jsonrpc":`, ErrorCodeParseError},
+		{`1`, ErrorCodeInvalidRequest},
+		{`"x"`, ErrorCodeInvalidRequest},
+		{`{"jsonrpc":"2.0","id":1,"method":2}`, ErrorCodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			r.Header.Set("Accept", "application/json, text/event-stream")
+			w := httptest.NewRecorder()
+			transport.Handle(w, r)
+			response := decodeJson[map[string]any](t, json.RawMessage(w.Body.Bytes()))
+			e, _ := response["error"].(map[string]any)
+			if w.Code != http.StatusBadRequest || e == nil || e["code"] != float64(tt.code) {
+				t.Fatalf("expected status 400 with code %d, got %d: %s", tt.code, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestHttpTransportCloseDetachesFromServer(t *testing.T) {
+	server := newTestServer()
+	transport := NewHttpTransport(server)
+	session := NewSession()
+	if err := transport.sessionStore.Create(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/event-stream")
+	r.Header.Set(sessionIdHeader, session.Id)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		transport.Handle(httptest.NewRecorder(), r)
+	}()
+
+	awaitStreams(t, transport, session.Id, 1)
+	transport.Close()
+
+	select {
+	case <-done:
+	case <-time.After(testTimeout):
+		t.Fatal("GET stream was not closed")
+	}
+	if len(server.broadcasters) != 0 {
+		t.Fatalf("expected the transport to be removed, got %d broadcasters", len(server.broadcasters))
+	}
+}
+
+// openStream opens a GET stream of a session on server and returns the messages received on it.
+func openStream(t *testing.T, server *httptest.Server, sessionId string) <-chan map[string]any {
+	t.Helper()
+	r, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Accept", "text/event-stream")
+	r.Header.Set(sessionIdHeader, sessionId)
+	res, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", res.StatusCode)
+	}
+	messages := make(chan map[string]any, 16)
+	go func() {
+		defer res.Body.Close()
+		defer close(messages)
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+			var message map[string]any
+			if err := json.Unmarshal([]byte(data), &message); err != nil {
+				t.Errorf("invalid event %q: %v", data, err)
+				continue
+			}
+			messages <- message
+		}
+	}()
+	return messages
+}
+
+// awaitStreams waits until count GET streams of the session are registered with transport.
+func awaitStreams(t *testing.T, transport *HttpTransport, sessionId string, count int) {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for time.Now().Before(deadline) {
+		transport.streamsMutex.Lock()
+		open := len(transport.streams[sessionId])
+		transport.streamsMutex.Unlock()
+		if open == count {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d streams of session %s", count, sessionId)
+}
+
+func TestHttpTransportNotifiesInitializedSessions(t *testing.T) {
+	transport := NewHttpTransport(newTestServer())
+	server := httptest.NewServer(http.HandlerFunc(transport.Handle))
+	defer server.Close()
+	defer transport.Close()
+
+	pending := NewSession()
+	if err := transport.sessionStore.Create(context.Background(), pending); err != nil {
+		t.Fatal(err)
+	}
+	pendingMessages := openStream(t, server, pending.Id)
+	awaitStreams(t, transport, pending.Id, 1)
+
+	if err := transport.Notify("notifications/test", nil); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected without initialized sessions, got %v", err)
+	}
+
+	initialized := NewSession()
+	initialized.Initialized = true
+	if err := transport.sessionStore.Create(context.Background(), initialized); err != nil {
+		t.Fatal(err)
+	}
+	messages := openStream(t, server, initialized.Id)
+	awaitStreams(t, transport, initialized.Id, 1)
+
+	if err := transport.Notify("notifications/test", nil); err != nil {
+		t.Fatalf("Notify returned %v", err)
+	}
+	select {
+	case message := <-messages:
+		if message["method"] != "notifications/test" {
+			t.Fatalf("expected the notification, got %v", message)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("notification was not delivered")
+	}
+	select {
+	case message := <-pendingMessages:
+		t.Fatalf("uninitialized session received %v", message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// post sends a POST request with body to transport, in the session with the given id unless it
+// is empty.
+func post(t *testing.T, transport *HttpTransport, sessionId string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionId != "" {
+		r.Header.Set(sessionIdHeader, sessionId)
+	}
+	w := httptest.NewRecorder()
+	transport.Handle(w, r)
+	return w
+}
+
+// events decodes the messages of an SSE response body.
+func events(t *testing.T, body string) []map[string]any {
+	t.Helper()
+	var messages []map[string]any
+	for line := range strings.Lines(body) {
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+		if !ok {
+			continue
+		}
+		var message map[string]any
+		if err := json.Unmarshal([]byte(data), &message); err != nil {
+			t.Fatalf("invalid event %q: %v", data, err)
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+func TestHttpTransportUpgradesPostsToEventStreams(t *testing.T) {
+	server := newTestServer()
+	server.AddTool(&Tool{
+		Name:        "notify",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			return nil, Notify(ctx, "notifications/test", nil)
+		},
+	})
+	server.AddTool(&Tool{
+		Name:        "quiet",
+		InputSchema: protocol.NewInputSchema(),
+		Handler: func(ctx context.Context, arguments *ToolArguments) (*protocol.CallToolsResult, error) {
+			return nil, nil
+		},
+	})
+	transport := NewHttpTransport(server)
+	session := NewSession()
+	session.ProtocolVersion = protocol.LatestProtocolVersion
+	if err := transport.sessionStore.Create(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+
+	w := post(t, transport, session.Id, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"notify","arguments":{}}}`)
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %q: %s", contentType, w.Body)
+	}
+	messages := events(t, w.Body.String())
+	if len(messages) != 2 || messages[0]["method"] != "notifications/test" || messages[1]["id"] != float64(1) {
+		t.Fatalf("expected the notification followed by the response, got %v", messages)
+	}
+
+	// Responses of requests that send nothing else are plain JSON.
+	w = post(t, transport, session.Id, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"quiet","arguments":{}}}`)
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Fatalf("expected a JSON response, got %q: %s", contentType, w.Body)
+	}
+}
+
+func TestHttpTransportBroadcastsOnGetStreams(t *testing.T) {
+	mcpServer := newTestServer()
+	transport := NewHttpTransport(mcpServer)
+	server := httptest.NewServer(http.HandlerFunc(transport.Handle))
+	defer server.Close()
+	defer transport.Close()
+
+	session := NewSession()
+	session.Initialized = true
+	if err := transport.sessionStore.Create(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	messages := openStream(t, server, session.Id)
+	awaitStreams(t, transport, session.Id, 1)
+
+	mcpServer.NotifyResourcesListChanged()
+	select {
+	case message := <-messages:
+		if message["method"] != "notifications/resources/list_changed" {
+			t.Fatalf("expected the list changed notification, got %v", message)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("notification was not delivered")
+	}
+}
+
+func TestHttpTransportManagesSessions(t *testing.T) {
+	transport := NewHttpTransport(newTestServer())
+
+	w := post(t, transport, "", `{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"protocolVersion":"`+protocol.LatestProtocolVersion+`","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`)
+	sessionId := w.Header().Get(sessionIdHeader)
+	if w.Code != http.StatusOK || sessionId == "" {
+		t.Fatalf("expected a session id, got %d %q: %s", w.Code, sessionId, w.Body)
+	}
+	session, err := transport.sessionStore.Get(context.Background(), sessionId)
+	if err != nil || session.ProtocolVersion != protocol.LatestProtocolVersion || session.ClientInfo.Name != "test" {
+		t.Fatalf("expected the negotiated session to be stored, got %+v, %v", session, err)
+	}
+	if w := post(t, transport, sessionId, `{"jsonrpc":"2.0","method":"notifications/initialized"}`); w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 for the notification, got %d", w.Code)
+	}
+	if w := post(t, transport, sessionId, `{"jsonrpc":"2.0","id":1,"method":"ping"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 in the session, got %d: %s", w.Code, w.Body)
+	}
+
+	if w := post(t, transport, "unknown", `{"jsonrpc":"2.0","id":1,"method":"ping"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown session, got %d", w.Code)
+	}
+	if w := post(t, transport, "", `{"jsonrpc":"2.0","id":1,"method":"ping"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without a session, got %d", w.Code)
+	}
+
+	r := httptest.NewRequest(http.MethodDelete, "/", nil)
+	r.Header.Set(sessionIdHeader, sessionId)
+	w = httptest.NewRecorder()
+	transport.Handle(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for DELETE, got %d", w.Code)
+	}
+	if w := post(t, transport, sessionId, `{"jsonrpc":"2.0","id":1,"method":"ping"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a deleted session, got %d", w.Code)
+	}
+}
+
+func TestHttpTransportNotifiesSubscribedResources(t *testing.T) {
+	mcpServer := newTestServer()
+	transport := NewHttpTransport(mcpServer)
+	server := httptest.NewServer(http.HandlerFunc(transport.Handle))
+	defer server.Close()
+	defer transport.Close()
+
+	session := NewSession()
+	session.Initialized = true
+	if err := transport.sessionStore.Create(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	messages := openStream(t, server, session.Id)
+	awaitStreams(t, transport, session.Id, 1)
+	if w := post(t, transport, session.Id, `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"file:///a"}}`); w.Code != http.StatusOK {
+		t.Fatalf("expected subscribe to succeed, got %d: %s", w.Code, w.Body)
+	}
+
+	mcpServer.NotifyResourceUpdated("file:///b")
+	mcpServer.NotifyResourceUpdated("file:///a")
+	select {
+	case message := <-messages:
+		if message["method"] != "notifications/resources/updated" || message["params"].(map[string]any)["uri"] != "file:///a" {
+			t.Fatalf("expected an update of file:///a, got %v", message)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("update was not delivered")
+	}
+}
@@ -0,0 +1,264 @@
+// Package uritemplate parses URI templates as specified in RFC 6570 and matches URIs against
+// them.
+//
+// All four levels of the RFC are supported. Since expansion is not reversible in general,
+// matching is a best effort: list and associative values are returned joined by commas, and
+// variables of expressions with several variables are assigned in order. Simple and reserved
+// expressions ({var} and {+var}) must expand to a non-empty value, while expressions with a
+// leading operator may be omitted.
+package uritemplate
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Template is a parsed URI template.
+type Template struct {
+	raw         string
+	expressions []*expression
+	regexp      *regexp.Regexp
+}
+
+type expression struct {
+	operator operator
+	varspecs []varspec
+}
+
+type varspec struct {
+	name    string
+	prefix  int
+	explode bool
+}
+
+// operator describes how the variables of an expression are expanded, see RFC 6570
+// appendix A.
+type operator struct {
+	first    string
+	sep      string
+	named    bool
+	reserved bool
+}
+
+var operators = map[byte]operator{
+	'+': {first: "", sep: ",", reserved: true},
+	'.': {first: ".", sep: "."},
+	'/': {first: "/", sep: "/"},
+	';': {first: ";", sep: ";", named: true},
+	'?': {first: "?", sep: "&", named: true},
+	'&': {first: "&", sep: "&", named: true},
+	'#': {first: "#", sep: ",", reserved: true},
+}
+
+var simpleOperator = operator{first: "", sep: ","}
+
+const (
+	unreservedChars = `A-Za-z0-9\-._~%`
+	reservedChars   = `:/?#\[\]@!$&'()*+,;=`
+)
+
+var varnameRegexp = regexp.MustCompile(`^(?:[A-Za-z0-9_]|%[0-9A-Fa-f]{2})(?:\.?(?:[A-Za-z0-9_]|%[0-9A-Fa-f]{2}))*$`)
+
+// Parse parses a URI template.
+func Parse(template string) (*Template, error) {
+	t := &Template{raw: template}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	rest := template
+	for rest != "" {
+		start := strings.IndexAny(rest, "{}")
+		if start < 0 {
+			pattern.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		if rest[start] == '}' {
+			return nil, fmt.Errorf("uritemplate: unexpected } in %q", template)
+		}
+		pattern.WriteString(regexp.QuoteMeta(rest[:start]))
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("uritemplate: unterminated expression in %q", template)
+		}
+		e, err := parseExpression(rest[start+1 : start+end])
+		if err != nil {
+			return nil, fmt.Errorf("uritemplate: %w in %q", err, template)
+		}
+		t.expressions = append(t.expressions, e)
+		pattern.WriteString(e.pattern())
+		rest = rest[start+end+1:]
+	}
+
+	pattern.WriteString("$")
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("uritemplate: %w", err)
+	}
+	t.regexp = re
+	return t, nil
+}
+
+// MustParse is like Parse but panics if the template cannot be parsed.
+func MustParse(template string) *Template {
+	t, err := Parse(template)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func parseExpression(s string) (*expression, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	e := &expression{operator: simpleOperator}
+	if op, ok := operators[s[0]]; ok {
+		e.operator = op
+		s = s[1:]
+	} else if strings.ContainsRune("=,!@|", rune(s[0])) {
+		return nil, fmt.Errorf("reserved operator %q", s[0])
+	}
+
+	for _, spec := range strings.Split(s, ",") {
+		v := varspec{}
+		if name, ok := strings.CutSuffix(spec, "*"); ok {
+			v.explode = true
+			spec = name
+		} else if name, length, ok := strings.Cut(spec, ":"); ok {
+			prefix, err := strconv.Atoi(length)
+			if err != nil || prefix < 1 || prefix > 9999 || length[0] == '0' {
+				return nil, fmt.Errorf("invalid prefix %q", length)
+			}
+			v.prefix = prefix
+			spec = name
+		}
+		if !varnameRegexp.MatchString(spec) {
+			return nil, fmt.Errorf("invalid variable name %q", spec)
+		}
+		v.name = spec
+		e.varspecs = append(e.varspecs, v)
+	}
+	return e, nil
+}
+
+// pattern returns a regular expression with a single group capturing the expansion of e.
+func (e *expression) pattern() string {
+	chars := unreservedChars
+	if e.operator.reserved {
+		chars += reservedChars
+	}
+	switch {
+	case e.operator.named:
+		// Names, values and their separators, e.g. ?a=1&b=2 or ;a=1;b
+		value := "[" + chars + ",=]*"
+		first := regexp.QuoteMeta(e.operator.first)
+		sep := regexp.QuoteMeta(e.operator.sep)
+		return "(" + "(?:" + first + value + "(?:" + sep + value + ")*)?" + ")"
+	case e.operator.first != "":
+		// Values with a leading operator, e.g. /a/b, .a.b or #a,b
+		value := "[" + chars + ",]*"
+		first := regexp.QuoteMeta(e.operator.first)
+		sep := regexp.QuoteMeta(e.operator.sep)
+		return "(" + "(?:" + first + value + "(?:" + sep + value + ")*)?" + ")"
+	default:
+		// Simple and reserved expressions have no operator marking them, so an empty expansion
+		// would make e.g. users://{id} match users://.
+		return "([" + chars + ",]+)"
+	}
+}
+
+// String returns the template as it was parsed.
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Variables returns the names of the variables of the template, in order of appearance.
+func (t *Template) Variables() []string {
+	names := make([]string, 0)
+	for _, e := range t.expressions {
+		for _, v := range e.varspecs {
+			names = append(names, v.name)
+		}
+	}
+	return names
+}
+
+// Match reports whether uri is an expansion of the template, and returns the values of the
+// variables that were found. Values are percent-decoded.
+func (t *Template) Match(uri string) (map[string]string, bool) {
+	matches := t.regexp.FindStringSubmatch(uri)
+	if matches == nil {
+		return nil, false
+	}
+	vars := make(map[string]string)
+	// A query expression also captures the continuation expressions that follow it, so the
+	// pairs of all named expressions are collected before they are assigned.
+	named := make(map[string][]string)
+	for i, e := range t.expressions {
+		if !e.match(matches[i+1], vars, named) {
+			return nil, false
+		}
+	}
+	for _, e := range t.expressions {
+		if !e.operator.named {
+			continue
+		}
+		for _, v := range e.varspecs {
+			if values, ok := named[v.name]; ok {
+				value, err := unescape(strings.Join(values, ","))
+				if err != nil || !v.fits(value) {
+					return nil, false
+				}
+				vars[v.name] = value
+			}
+		}
+	}
+	return vars, true
+}
+
+func (e *expression) match(s string, vars map[string]string, named map[string][]string) bool {
+	if s == "" {
+		return true
+	}
+	s = strings.TrimPrefix(s, e.operator.first)
+
+	if e.operator.named {
+		for _, pair := range strings.Split(s, e.operator.sep) {
+			name, value, _ := strings.Cut(pair, "=")
+			named[name] = append(named[name], value)
+		}
+		return true
+	}
+
+	var parts []string
+	if len(e.varspecs) == 1 {
+		if e.varspecs[0].explode {
+			parts = []string{strings.Join(strings.Split(s, e.operator.sep), ",")}
+		} else {
+			parts = []string{s}
+		}
+	} else {
+		parts = strings.SplitN(s, e.operator.sep, len(e.varspecs))
+	}
+	for i, part := range parts {
+		value, err := unescape(part)
+		if err != nil || !e.varspecs[i].fits(value) {
+			return false
+		}
+		vars[e.varspecs[i].name] = value
+	}
+	return true
+}
+
+// fits reports whether value is no longer than the prefix length of v, if it has one.
+func (v varspec) fits(value string) bool {
+	return v.prefix == 0 || utf8.RuneCountInString(value) <= v.prefix
+}
+
+func unescape(s string) (string, error) {
+	return url.PathUnescape(s)
+}
@@ -0,0 +1,110 @@
+package uritemplate
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		template string
+		uri      string
+		// want is nil if uri must not match.
+		want map[string]string
+	}{
+		// Simple expansion
+		{"users://{id}", "users://42", map[string]string{"id": "42"}},
+		{"users://{id}", "users://", nil},
+		{"users://{id}", "users://a/b", nil},
+		{"users://{id}", "users://hello%20world", map[string]string{"id": "hello world"}},
+		{"users://{id}/posts", "users:///posts", nil},
+		{"map://{x,y}", "map://1024,768", map[string]string{"x": "1024", "y": "768"}},
+		{"list://{list*}", "list://red,green", map[string]string{"list": "red,green"}},
+
+		// Reserved expansion
+		{"file://{+path}", "file:///etc/hosts", map[string]string{"path": "/etc/hosts"}},
+		{"file://{+path}", "file://", nil},
+
+		// Fragment expansion
+		{"doc://x{#section}", "doc://x#intro", map[string]string{"section": "intro"}},
+		{"doc://x{#section}", "doc://x", map[string]string{}},
+
+		// Label expansion
+		{"host://www{.domain}", "host://www.example", map[string]string{"domain": "example"}},
+		{"host://www{.parts*}", "host://www.example.com", map[string]string{"parts": "example,com"}},
+
+		// Path segment expansion
+		{"repo://x{/owner,name}", "repo://x/golang/go", map[string]string{"owner": "golang", "name": "go"}},
+		{"repo://x{/path*}", "repo://x/a/b/c", map[string]string{"path": "a,b,c"}},
+		{"repo://x{/owner}", "repo://x", map[string]string{}},
+
+		// Path-style parameter expansion
+		{"map://x{;lat,long}", "map://x;lat=1;long=2", map[string]string{"lat": "1", "long": "2"}},
+
+		// Query expansion and continuation
+		{"search://x{?q,lang}", "search://x?q=go&lang=en", map[string]string{"q": "go", "lang": "en"}},
+		{"search://x{?q,lang}", "search://x?lang=en", map[string]string{"lang": "en"}},
+		{"search://x{?q}", "search://x", map[string]string{}},
+		{"search://x{?q}{&page}", "search://x?q=go&page=2", map[string]string{"q": "go", "page": "2"}},
+		{"search://x{?tags*}", "search://x?tags=a&tags=b", map[string]string{"tags": "a,b"}},
+
+		// Prefix values
+		{"users://{id:3}", "users://abc", map[string]string{"id": "abc"}},
+		{"users://{id:3}", "users://ab", map[string]string{"id": "ab"}},
+		{"users://{id:3}", "users://abcdef", nil},
+		{"users://{id:3}", "users://%C3%A4%C3%B6%C3%BC", map[string]string{"id": "äöü"}},
+		{"search://x{?q:2}", "search://x?q=abc", nil},
+
+		// Literals
+		{"gomcp://posts/{id}", "gomcp://posts/", nil},
+		{"gomcp://posts/{id}", "gomcp://pages/1", nil},
+		{"gomcp://posts", "gomcp://posts", map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template+" "+tt.uri, func(t *testing.T) {
+			vars, ok := MustParse(tt.template).Match(tt.uri)
+			if tt.want == nil {
+				if ok {
+					t.Fatalf("expected no match, got %v", vars)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected a match")
+			}
+			if !maps.Equal(vars, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, vars)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"users://{id",
+		"users://id}",
+		"users://{}",
+		"users://{=id}",
+		"users://{id:0}",
+		"users://{id:10000}",
+		"users://{id:x}",
+		"users://{a b}",
+		"users://{id,}",
+	}
+	for _, template := range tests {
+		t.Run(template, func(t *testing.T) {
+			if _, err := Parse(template); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestVariables(t *testing.T) {
+	got := MustParse("x://{a}{/b*}{?c,d:2}").Variables()
+	want := []string{"a", "b", "c", "d"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
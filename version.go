@@ -0,0 +1,165 @@
+package gomcp
+
+import (
+	"context"
+	"regexp"
+	"slices"
+
+	"github.com/cfichtmueller/gomcp/protocol"
+	"github.com/cfichtmueller/gomcp/schema"
+)
+
+var protocolVersionRegexp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// negotiateProtocolVersion returns the latest supported protocol version that is not newer than
+// the requested one. Since versions are dates, a client that speaks a version also knows the
+// ones before it.
+func negotiateProtocolVersion(requested string) (string, bool) {
+	if !protocolVersionRegexp.MatchString(requested) {
+		return "", false
+	}
+	for _, version := range protocol.SupportedProtocolVersions {
+		if version <= requested {
+			return version, true
+		}
+	}
+	return "", false
+}
+
+// supportedProtocolVersion reports whether version is one of the protocol versions the server
+// speaks.
+func supportedProtocolVersion(version string) bool {
+	return slices.Contains(protocol.SupportedProtocolVersions, version)
+}
+
+// protocolVersionAtLeast reports whether the protocol version negotiated with the client whose
+// request is handled in ctx is version or newer. Without a session, the latest version is
+// assumed.
+func protocolVersionAtLeast(ctx context.Context, version string) bool {
+	session := sessionFromContext(ctx)
+	if session == nil || session.protocolVersion() == "" {
+		return true
+	}
+	return session.protocolVersion() >= version
+}
+
+// The following functions remove fields that clients of older protocol versions do not know.
+// They return copies, since the values may be shared with the registries or the caller.
+
+func downgradeTool(ctx context.Context, tool *protocol.Tool) *protocol.Tool {
+	if protocolVersionAtLeast(ctx, protocol.ProtocolVersion20250618) {
+		return tool
+	}
+	t := *tool
+	t.Title = ""
+	t.OutputSchema = nil
+	return &t
+}
+
+func downgradeCallToolsResult(ctx context.Context, result *protocol.CallToolsResult) *protocol.CallToolsResult {
+	if result == nil || protocolVersionAtLeast(ctx, protocol.ProtocolVersion20250618) {
+		return result
+	}
+	r := *result
+	r.StructuredContent = nil
+	r.Content = make(schema.A, 0, len(result.Content))
+	for _, content := range result.Content {
+		if contentSupported(ctx, content) {
+			r.Content = append(r.Content, content)
+		}
+	}
+	return &r
+}
+
+// downgradeGetPromptResult removes the messages whose content type the client does not know.
+func downgradeGetPromptResult(ctx context.Context, result *protocol.GetPromptResult) *protocol.GetPromptResult {
+	if protocolVersionAtLeast(ctx, protocol.ProtocolVersion20250618) {
+		return result
+	}
+	r := *result
+	r.Messages = make([]*protocol.PromptMessage, 0, len(result.Messages))
+	for _, message := range result.Messages {
+		if contentSupported(ctx, message.Content) {
+			r.Messages = append(r.Messages, message)
+		}
+	}
+	return &r
+}
+
+// contentSupported reports whether the client knows the type of a content block. Audio content
+// was added in 2025-03-26, resource links in 2025-06-18.
+func contentSupported(ctx context.Context, content any) bool {
+	switch contentType(content) {
+	case "audio":
+		return protocolVersionAtLeast(ctx, protocol.ProtocolVersion20250326)
+	case "resource_link":
+		return protocolVersionAtLeast(ctx, protocol.ProtocolVersion20250618)
+	}
+	return true
+}
+
+// contentType returns the type of a content block, whether it was built with the types of the
+// protocol package or as a map.
+func contentType(content any) string {
+	switch c := content.(type) {
+	case *protocol.TextContent:
+		return c.Type
+	case *protocol.ImageContent:
+		return c.Type
+	case *protocol.AudioContent:
+		return c.Type
+	case *protocol.EmbeddedResource:
+		return c.Type
+	case *protocol.ResourceLink:
+		return c.Type
+	case map[string]any:
+		t, _ := c["type"].(string)
+		return t
+	case schema.M:
+		t, _ := c["type"].(string)
+		return t
+	}
+	return ""
+}
+
+func downgradePrompt(ctx context.Context, prompt *protocol.Prompt) *protocol.Prompt {
+	if protocolVersionAtLeast(ctx, protocol.ProtocolVersion20250618) {
+		return prompt
+	}
+	p := *prompt
+	p.Title = ""
+	p.Arguments = make([]*protocol.PromptArgument, 0, len(prompt.Arguments))
+	for _, argument := range prompt.Arguments {
+		a := *argument
+		a.Title = ""
+		p.Arguments = append(p.Arguments, &a)
+	}
+	return &p
+}
+
+func downgradeResource(ctx context.Context, resource *protocol.Resource) *protocol.Resource {
+	if protocolVersionAtLeast(ctx, protocol.ProtocolVersion20250618) {
+		return resource
+	}
+	r := *resource
+	r.Title = ""
+	return &r
+}
+
+func downgradeResourceTemplate(ctx context.Context, template *protocol.ResourceTemplate) *protocol.ResourceTemplate {
+	if protocolVersionAtLeast(ctx, protocol.ProtocolVersion20250618) {
+		return template
+	}
+	t := *template
+	t.Title = ""
+	return &t
+}
+
+func downgradeServerInfo(ctx context.Context, info *protocol.ServerInfo) *protocol.ServerInfo {
+	if protocolVersionAtLeast(ctx, protocol.ProtocolVersion20250618) {
+		return info
+	}
+	i := *info
+	i.Title = ""
+	return &i
+}